@@ -1,50 +1,320 @@
-CREATE TABLE customer (
-    customer_id SERIAL PRIMARY KEY,
-    name VARCHAR(255) NOT NULL,
-    phone_number VARCHAR(255) NOT NULL,
-    address VARCHAR(255) DEFAULT '',
-    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
-
-
-CREATE TABLE employee (
-    employee_id SERIAL PRIMARY KEY,
-    name VARCHAR(255) NOT NULL,
-    phone_number VARCHAR(255) NOT NULL,
-    address VARCHAR(255) DEFAULT '',
-    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
-
-CREATE TABLE product (
-    product_id SERIAL PRIMARY KEY,
-    product_name VARCHAR(255) NOT NULL,
-    unit VARCHAR(255) NOT NULL,
-    price INT NOT NULL,
-    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
-
-CREATE TABLE transaction (
-    transaction_id SERIAL PRIMARY KEY,
-    customer_id INT NOT NULL,
-    employee_id INT NOT NULL,
-    bill_date VARCHAR(255),
-    entry_date VARCHAR(255),
-    finish_date VARCHAR(255),
-    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    FOREIGN KEY (customer_id) REFERENCES customer(customer_id),
-    FOREIGN KEY (employee_id) REFERENCES employee(employee_id)
-);
-
-CREATE TABLE transaction_detail (
-    transaction_detail_id SERIAL PRIMARY KEY,
-    transaction_id INT NOT NULL,
-    product_id INT NOT NULL,
-    product_price INT NOT NULL,
-    qty INT NOT NULL,
-    FOREIGN KEY (transaction_id) REFERENCES transaction(transaction_id),
-    FOREIGN KEY (product_id) REFERENCES product(product_id)
-);
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- Lower case without accents, immutable so the search indexes can use it
+CREATE FUNCTION search_normalize(value TEXT) RETURNS TEXT AS $$
+    SELECT lower(public.unaccent('public.unaccent', value))
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+CREATE TABLE customer (
+    customer_id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    phone_number VARCHAR(255) NOT NULL,
+    address VARCHAR(255) DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+
+CREATE TABLE employee (
+    employee_id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    phone_number VARCHAR(255) NOT NULL,
+    address VARCHAR(255) DEFAULT '',
+    username VARCHAR(50) UNIQUE,
+    password_hash VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE TABLE role (
+    role_id SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE
+);
+
+CREATE TABLE employee_role (
+    employee_id INT NOT NULL,
+    role_id INT NOT NULL,
+    PRIMARY KEY (employee_id, role_id),
+    FOREIGN KEY (employee_id) REFERENCES employee(employee_id) ON DELETE CASCADE,
+    FOREIGN KEY (role_id) REFERENCES role(role_id)
+);
+
+-- Groups of products on the catalogue, shown by sort_order
+CREATE TABLE category (
+    category_id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    sort_order INT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE product (
+    product_id SERIAL PRIMARY KEY,
+    product_name VARCHAR(255) NOT NULL,
+    unit VARCHAR(255) NOT NULL,
+    pricing_model VARCHAR(20) NOT NULL DEFAULT 'per_piece' CHECK (pricing_model IN ('per_kg', 'per_piece', 'flat')),
+    min_weight NUMERIC(10,2) NOT NULL DEFAULT 0,
+    tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    product_type VARCHAR(20) NOT NULL DEFAULT 'service' CHECK (product_type IN ('service', 'package')),
+    quota NUMERIC(10,2) NOT NULL DEFAULT 0,
+    category_id INT,
+    description TEXT NOT NULL DEFAULT '',
+    sort_order INT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    FOREIGN KEY (category_id) REFERENCES category(category_id)
+);
+
+-- Products a package covers with its quota
+CREATE TABLE package_product (
+    package_id INT NOT NULL,
+    product_id INT NOT NULL,
+    PRIMARY KEY (package_id, product_id),
+    FOREIGN KEY (package_id) REFERENCES product(product_id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES product(product_id) ON DELETE CASCADE
+);
+
+-- Price history, the only source of product prices, a bill is charged the price effective on its bill date
+CREATE TABLE product_price (
+    product_price_id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL,
+    price INT NOT NULL CHECK (price >= 0),
+    effective_from DATE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (product_id, effective_from),
+    FOREIGN KEY (product_id) REFERENCES product(product_id) ON DELETE CASCADE
+);
+
+CREATE TABLE customer_subscription (
+    subscription_id SERIAL PRIMARY KEY,
+    customer_id INT NOT NULL,
+    package_id INT NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    quota NUMERIC(10,2) NOT NULL,
+    used NUMERIC(10,2) NOT NULL DEFAULT 0 CHECK (used >= 0 AND used <= quota),
+    price INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date >= start_date),
+    FOREIGN KEY (customer_id) REFERENCES customer(customer_id),
+    FOREIGN KEY (package_id) REFERENCES product(product_id)
+);
+
+CREATE TABLE service_tier (
+    service_tier_id SERIAL PRIMARY KEY,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    surcharge_type VARCHAR(20) NOT NULL CHECK (surcharge_type IN ('percent', 'fixed')),
+    surcharge_value INT NOT NULL DEFAULT 0 CHECK (surcharge_value >= 0),
+    turnaround_hours INT NOT NULL CHECK (turnaround_hours > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE promo (
+    promo_id SERIAL PRIMARY KEY,
+    code VARCHAR(30) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    discount_type VARCHAR(20) NOT NULL CHECK (discount_type IN ('percent', 'fixed')),
+    discount_value INT NOT NULL CHECK (discount_value > 0),
+    max_discount INT NOT NULL DEFAULT 0,
+    min_spend INT NOT NULL DEFAULT 0,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    usage_limit INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Products a promo is restricted to, a promo without rows applies to every product
+CREATE TABLE promo_product (
+    promo_id INT NOT NULL,
+    product_id INT NOT NULL,
+    PRIMARY KEY (promo_id, product_id),
+    FOREIGN KEY (promo_id) REFERENCES promo(promo_id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES product(product_id) ON DELETE CASCADE
+);
+
+-- Last bill number given per branch and day
+CREATE TABLE bill_sequence (
+    branch_code VARCHAR(10) NOT NULL,
+    bill_day DATE NOT NULL,
+    last_number INT NOT NULL,
+    PRIMARY KEY (branch_code, bill_day)
+);
+
+CREATE TABLE transaction (
+    transaction_id SERIAL PRIMARY KEY,
+    bill_number VARCHAR(30) NOT NULL UNIQUE,
+    customer_id INT NOT NULL,
+    employee_id INT NOT NULL,
+    bill_date DATE NOT NULL,
+    entry_date TIMESTAMPTZ NOT NULL,
+    finish_date TIMESTAMPTZ NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'received' CHECK (status IN ('received', 'washing', 'ready', 'picked_up', 'cancelled')),
+    cancel_reason VARCHAR(255) NOT NULL DEFAULT '',
+    cancelled_at TIMESTAMP,
+    service_tier VARCHAR(20) NOT NULL DEFAULT 'regular',
+    surcharge_type VARCHAR(20) NOT NULL DEFAULT 'fixed' CHECK (surcharge_type IN ('percent', 'fixed')),
+    surcharge_value INT NOT NULL DEFAULT 0,
+    surcharge INT NOT NULL DEFAULT 0,
+    promo_id INT,
+    discount INT NOT NULL DEFAULT 0,
+    member_tier VARCHAR(20) NOT NULL DEFAULT 'member',
+    member_discount_percent INT NOT NULL DEFAULT 0,
+    member_discount INT NOT NULL DEFAULT 0,
+    points_redeemed INT NOT NULL DEFAULT 0 CHECK (points_redeemed >= 0),
+    points_discount INT NOT NULL DEFAULT 0,
+    subtotal INT NOT NULL DEFAULT 0,
+    tax_rate INT NOT NULL DEFAULT 0,
+    tax INT NOT NULL DEFAULT 0,
+    total_bill INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (customer_id) REFERENCES customer(customer_id),
+    FOREIGN KEY (employee_id) REFERENCES employee(employee_id),
+    FOREIGN KEY (promo_id) REFERENCES promo(promo_id)
+);
+
+CREATE TABLE transaction_detail (
+    transaction_detail_id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL,
+    product_id INT NOT NULL,
+    product_price INT NOT NULL,
+    qty NUMERIC(10,2) NOT NULL,
+    line_total INT NOT NULL,
+    tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    subscription_id INT,
+    quota_used NUMERIC(10,2) NOT NULL DEFAULT 0,
+    removed_at TIMESTAMP,
+    FOREIGN KEY (transaction_id) REFERENCES transaction(transaction_id),
+    FOREIGN KEY (product_id) REFERENCES product(product_id),
+    FOREIGN KEY (subscription_id) REFERENCES customer_subscription(subscription_id)
+);
+
+CREATE TABLE transaction_status_history (
+    history_id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (transaction_id) REFERENCES transaction(transaction_id)
+);
+
+CREATE TABLE payment (
+    payment_id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL,
+    amount INT NOT NULL CHECK (amount > 0),
+    method VARCHAR(20) NOT NULL CHECK (method IN ('cash', 'transfer', 'e_wallet', 'wallet')),
+    payment_type VARCHAR(20) NOT NULL DEFAULT 'payment' CHECK (payment_type IN ('deposit', 'payment')),
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    paid_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (transaction_id) REFERENCES transaction(transaction_id)
+);
+
+-- Points ledger of a customer, the balance is the sum of the entries
+CREATE TABLE customer_point (
+    point_id SERIAL PRIMARY KEY,
+    customer_id INT NOT NULL,
+    transaction_id INT,
+    points INT NOT NULL,
+    entry_type VARCHAR(20) NOT NULL CHECK (entry_type IN ('earn', 'redeem', 'reverse', 'refund')),
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (customer_id) REFERENCES customer(customer_id),
+    FOREIGN KEY (transaction_id) REFERENCES transaction(transaction_id)
+);
+
+-- Prepaid balance of a customer, locked on every entry so it can not be overdrawn
+CREATE TABLE customer_wallet (
+    customer_id INT PRIMARY KEY,
+    balance INT NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (customer_id) REFERENCES customer(customer_id)
+);
+
+CREATE TABLE wallet_entry (
+    wallet_entry_id SERIAL PRIMARY KEY,
+    customer_id INT NOT NULL,
+    transaction_id INT,
+    payment_id INT,
+    amount INT NOT NULL,
+    balance_after INT NOT NULL,
+    entry_type VARCHAR(20) NOT NULL CHECK (entry_type IN ('top_up', 'debit', 'refund', 'merge')),
+    method VARCHAR(20) NOT NULL DEFAULT '',
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (customer_id) REFERENCES customer_wallet(customer_id),
+    FOREIGN KEY (transaction_id) REFERENCES transaction(transaction_id),
+    FOREIGN KEY (payment_id) REFERENCES payment(payment_id)
+);
+
+-- Consumables used by the services, stock is kept with every stock movement
+CREATE TABLE supply (
+    supply_id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    unit VARCHAR(20) NOT NULL,
+    stock NUMERIC(12,3) NOT NULL DEFAULT 0,
+    reorder_level NUMERIC(12,3) NOT NULL DEFAULT 0 CHECK (reorder_level >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Bill of materials, the supply used by one unit of the product qty
+CREATE TABLE product_supply (
+    product_id INT NOT NULL,
+    supply_id INT NOT NULL,
+    usage_per_unit NUMERIC(12,3) NOT NULL CHECK (usage_per_unit > 0),
+    PRIMARY KEY (product_id, supply_id),
+    FOREIGN KEY (product_id) REFERENCES product(product_id) ON DELETE CASCADE,
+    FOREIGN KEY (supply_id) REFERENCES supply(supply_id)
+);
+
+-- Stock ledger of a supply, taken by bills or entered by hand
+CREATE TABLE stock_movement (
+    stock_movement_id SERIAL PRIMARY KEY,
+    supply_id INT NOT NULL,
+    transaction_id INT,
+    employee_id INT,
+    change NUMERIC(12,3) NOT NULL,
+    stock_after NUMERIC(12,3) NOT NULL,
+    movement_type VARCHAR(20) NOT NULL CHECK (movement_type IN ('usage', 'return', 'restock', 'adjustment')),
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (supply_id) REFERENCES supply(supply_id),
+    FOREIGN KEY (transaction_id) REFERENCES transaction(transaction_id),
+    FOREIGN KEY (employee_id) REFERENCES employee(employee_id)
+);
+
+CREATE UNIQUE INDEX idx_customer_phone_number ON customer(phone_number) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transaction_status ON transaction(status);
+CREATE INDEX idx_transaction_entry_date ON transaction(entry_date);
+CREATE INDEX idx_transaction_finish_date ON transaction(finish_date);
+CREATE INDEX idx_transaction_detail_transaction_id ON transaction_detail(transaction_id);
+CREATE INDEX idx_transaction_status_history_transaction_id ON transaction_status_history(transaction_id);
+CREATE INDEX idx_payment_transaction_id ON payment(transaction_id);
+CREATE INDEX idx_transaction_promo_customer ON transaction(promo_id, customer_id);
+CREATE INDEX idx_customer_point_customer_id ON customer_point(customer_id);
+CREATE INDEX idx_customer_point_transaction_id ON customer_point(transaction_id);
+CREATE INDEX idx_wallet_entry_customer_id ON wallet_entry(customer_id);
+CREATE INDEX idx_wallet_entry_transaction_id ON wallet_entry(transaction_id);
+CREATE INDEX idx_customer_subscription_customer_id ON customer_subscription(customer_id);
+CREATE INDEX idx_stock_movement_supply_id ON stock_movement(supply_id);
+CREATE INDEX idx_stock_movement_transaction_id ON stock_movement(transaction_id);
+CREATE INDEX idx_customer_search ON customer USING GIN (to_tsvector('simple', search_normalize(name || ' ' || COALESCE(address,''))));
+CREATE INDEX idx_customer_name_trgm ON customer USING GIN (search_normalize(name) gin_trgm_ops);
+CREATE INDEX idx_customer_phone_number_trgm ON customer USING GIN (phone_number gin_trgm_ops);
+CREATE INDEX idx_employee_search ON employee USING GIN (to_tsvector('simple', search_normalize(name || ' ' || COALESCE(address,'') || ' ' || COALESCE(username,''))));
+CREATE INDEX idx_employee_name_trgm ON employee USING GIN (search_normalize(name) gin_trgm_ops);
+CREATE INDEX idx_employee_phone_number_trgm ON employee USING GIN (phone_number gin_trgm_ops);
+CREATE INDEX idx_product_search ON product USING GIN (to_tsvector('simple', search_normalize(product_name)));
+CREATE INDEX idx_product_name_trgm ON product USING GIN (search_normalize(product_name) gin_trgm_ops);
@@ -1,39 +1,192 @@
-INSERT INTO customer (name, phone_number, address)
-VALUES
-('John Doe', '555-1234', '123 Elm St'),
-('Jane Smith', '555-5678', '456 Oak St'),
-('Michael Johnson', '555-8765', '789 Pine St'),
-('Emily Davis', '555-4321', '321 Maple Ave'),
-('Robert Brown', '555-1111', '654 Cedar St');
-
-INSERT INTO employee (name, phone_number, address)
-VALUES
-('Alice Williams', '555-2222', '987 Willow St'),
-('David Harris', '555-3333', '123 Birch St'),
-('Sophia Martinez', '555-4444', '456 Redwood St'),
-('James Wilson', '555-5555', '789 Palm St'),
-('Olivia Garcia', '555-6666', '321 Cypress Ave');
-
-INSERT INTO product (product_name, unit, price)
-VALUES
-('Shampoo', 'bottle', 10000),
-('Soap', 'bar', 5000),
-('Toothpaste', 'tube', 15000),
-('Conditioner', 'bottle', 12000),
-('Body Lotion', 'bottle', 25000);
-
-INSERT INTO transaction (customer_id, employee_id, bill_date, entry_date, finish_date) 
-VALUES 
-(1, 1, '01-10-2024', '01-10-2024', '05-10-2024'),
-(2, 2, '02-10-2024', '02-10-2024', '06-10-2024'),
-(3, 3, '03-10-2024', '03-10-2024', '07-10-2024'),
-(4, 4, '04-10-2024', '04-10-2024', '08-10-2024'),
-(5, 5, '05-10-2024', '05-10-2024', '09-10-2024');
-
-INSERT INTO transaction_detail (transaction_id, product_id, product_price, qty)
-VALUES
-(1, 1, 10000, 2),
-(2, 2, 5000, 5),
-(3, 3, 15000, 3),
-(4, 4, 12000, 4),
-(5, 5, 25000, 1);
+INSERT INTO customer (name, phone_number, address)
+VALUES
+('John Doe', '+6281234561234', '123 Elm St'),
+('Jane Smith', '+6281234565678', '456 Oak St'),
+('Michael Johnson', '+6281234568765', '789 Pine St'),
+('Emily Davis', '+6281234564321', '321 Maple Ave'),
+('Robert Brown', '+6281234561111', '654 Cedar St');
+
+-- Every seeded employee logs in with password "password123"
+INSERT INTO employee (name, phone_number, address, username, password_hash)
+VALUES
+('Alice Williams', '555-2222', '987 Willow St', 'alice', '$2a$10$vHaIGrtZV1a9C9DqaVjShuPt/htqzuK6TT8zpEwb/gjoLthA1APgG'),
+('David Harris', '555-3333', '123 Birch St', 'david', '$2a$10$vHaIGrtZV1a9C9DqaVjShuPt/htqzuK6TT8zpEwb/gjoLthA1APgG'),
+('Sophia Martinez', '555-4444', '456 Redwood St', 'sophia', '$2a$10$vHaIGrtZV1a9C9DqaVjShuPt/htqzuK6TT8zpEwb/gjoLthA1APgG'),
+('James Wilson', '555-5555', '789 Palm St', 'james', '$2a$10$vHaIGrtZV1a9C9DqaVjShuPt/htqzuK6TT8zpEwb/gjoLthA1APgG'),
+('Olivia Garcia', '555-6666', '321 Cypress Ave', 'olivia', '$2a$10$vHaIGrtZV1a9C9DqaVjShuPt/htqzuK6TT8zpEwb/gjoLthA1APgG');
+
+INSERT INTO role (name)
+VALUES
+('admin'),
+('cashier'),
+('washer');
+
+-- alice is admin, david and james are cashier, sophia and olivia are washer
+INSERT INTO employee_role (employee_id, role_id)
+VALUES
+(1, 1),
+(2, 2),
+(3, 3),
+(4, 2),
+(5, 3);
+
+INSERT INTO category (name, description, sort_order)
+VALUES
+('Cuci Lipat', 'Wash & fold, priced per kg', 1),
+('Dry Clean', 'Dry cleaning for suits, dresses and delicate fabrics', 2),
+('Setrika', 'Ironing only', 3),
+('Tambahan', 'Add-ons sold at the counter', 4);
+
+INSERT INTO product (product_name, unit)
+VALUES
+('Shampoo', 'bottle'),
+('Soap', 'bar'),
+('Toothpaste', 'tube'),
+('Conditioner', 'bottle'),
+('Body Lotion', 'bottle');
+
+-- Laundry services are priced with PPN included
+INSERT INTO product (product_name, unit, pricing_model, min_weight, tax_inclusive)
+VALUES
+('Cuci Setrika', 'kg', 'per_kg', 3, TRUE),
+('Bed Cover', 'pcs', 'per_piece', 0, TRUE),
+('Antar Jemput', 'order', 'flat', 0, FALSE);
+
+-- Monthly package with 30 kg of Cuci Setrika
+INSERT INTO product (product_name, unit, pricing_model, min_weight, tax_inclusive, product_type, quota)
+VALUES
+('Paket Bulanan 30 kg', 'month', 'flat', 0, TRUE, 'package', 30);
+
+INSERT INTO product (product_name, unit, pricing_model, min_weight, tax_inclusive)
+VALUES
+('Dry Clean Jas', 'pcs', 'per_piece', 0, TRUE),
+('Setrika Saja', 'kg', 'per_kg', 3, TRUE);
+
+UPDATE product SET category_id = 1, sort_order = 1, description = 'Washed, dried, ironed and folded' WHERE product_id = 6;
+UPDATE product SET category_id = 1, sort_order = 2, description = 'Single or double bed cover' WHERE product_id = 7;
+UPDATE product SET category_id = 1, sort_order = 3, description = '30 kg of Cuci Setrika within a month' WHERE product_id = 9;
+UPDATE product SET category_id = 2, sort_order = 1, description = 'Two or three piece suit' WHERE product_id = 10;
+UPDATE product SET category_id = 3, sort_order = 1, description = 'Ironed and folded, without washing' WHERE product_id = 11;
+UPDATE product SET category_id = 4, sort_order = 1, description = 'Pick up and delivery' WHERE product_id = 8;
+-- Toiletries are not sold anymore, they are kept for the old bills
+UPDATE product SET category_id = 4, sort_order = 2, active = FALSE WHERE product_id IN (1, 2, 3, 4, 5);
+
+INSERT INTO product_price (product_id, price, effective_from)
+VALUES
+(1, 10000, '2024-01-01'),
+(2, 5000, '2024-01-01'),
+(3, 15000, '2024-01-01'),
+(4, 12000, '2024-01-01'),
+(5, 25000, '2024-01-01'),
+(6, 7000, '2024-01-01'),
+(7, 25000, '2024-01-01'),
+(8, 15000, '2024-01-01'),
+(9, 180000, '2024-01-01'),
+(10, 35000, '2024-01-01'),
+(11, 5000, '2024-01-01');
+
+INSERT INTO package_product (package_id, product_id)
+VALUES
+(9, 6);
+
+INSERT INTO customer_subscription (customer_id, package_id, start_date, end_date, quota, price)
+VALUES
+(4, 9, '2024-10-01', '2024-10-31', 30, 180000);
+
+INSERT INTO service_tier (code, name, surcharge_type, surcharge_value, turnaround_hours)
+VALUES
+('regular', 'Regular', 'fixed', 0, 72),
+('express', 'Express', 'percent', 50, 24),
+('same_day', 'Same Day', 'percent', 100, 6);
+
+-- WELCOME is once per customer, BEDCOVER20 only discounts Bed Cover
+INSERT INTO promo (code, name, discount_type, discount_value, max_discount, min_spend, starts_at, ends_at, usage_limit)
+VALUES
+('WELCOME', 'Welcome Discount', 'fixed', 10000, 0, 30000, '2024-01-01', '2030-12-31 23:59:59', 1),
+('BEDCOVER20', 'Bed Cover 20%', 'percent', 20, 25000, 0, '2024-01-01', '2030-12-31 23:59:59', 0);
+
+INSERT INTO promo_product (promo_id, product_id)
+VALUES
+(2, 7);
+
+INSERT INTO transaction (bill_number, customer_id, employee_id, bill_date, entry_date, finish_date, subtotal, total_bill) 
+VALUES 
+('EL-JKT-20241001-0001', 1, 1, '2024-10-01', '2024-10-01', '2024-10-05', 20000, 20000),
+('EL-JKT-20241002-0001', 2, 2, '2024-10-02', '2024-10-02', '2024-10-06', 25000, 25000),
+('EL-JKT-20241003-0001', 3, 3, '2024-10-03', '2024-10-03', '2024-10-07', 45000, 45000),
+('EL-JKT-20241004-0001', 4, 4, '2024-10-04', '2024-10-04', '2024-10-08', 48000, 48000),
+('EL-JKT-20241005-0001', 5, 5, '2024-10-05', '2024-10-05', '2024-10-09', 25000, 25000);
+
+INSERT INTO bill_sequence (branch_code, bill_day, last_number)
+VALUES
+('JKT', '2024-10-01', 1),
+('JKT', '2024-10-02', 1),
+('JKT', '2024-10-03', 1),
+('JKT', '2024-10-04', 1),
+('JKT', '2024-10-05', 1);
+
+INSERT INTO transaction_detail (transaction_id, product_id, product_price, qty, line_total)
+VALUES
+(1, 1, 10000, 2, 20000),
+(2, 2, 5000, 5, 25000),
+(3, 3, 15000, 3, 45000),
+(4, 4, 12000, 4, 48000),
+(5, 5, 25000, 1, 25000);
+
+INSERT INTO transaction_status_history (transaction_id, to_status, note)
+VALUES
+(1, 'received', 'bill created'),
+(2, 'received', 'bill created'),
+(3, 'received', 'bill created'),
+(4, 'received', 'bill created'),
+(5, 'received', 'bill created');
+
+INSERT INTO payment (transaction_id, amount, method, payment_type)
+VALUES
+(1, 20000, 'cash', 'payment'),
+(2, 10000, 'cash', 'deposit'),
+(3, 45000, 'transfer', 'payment');
+
+INSERT INTO customer_point (customer_id, transaction_id, points, entry_type, note)
+VALUES
+(1, 1, 20, 'earn', 'bill paid'),
+(3, 3, 45, 'earn', 'bill paid');
+
+INSERT INTO customer_wallet (customer_id, balance)
+VALUES
+(2, 100000);
+
+INSERT INTO wallet_entry (customer_id, amount, balance_after, entry_type, method, note)
+VALUES
+(2, 100000, 100000, 'top_up', 'transfer', 'monthly deposit');
+
+
+INSERT INTO supply (name, unit, stock, reorder_level)
+VALUES
+('Deterjen', 'ml', 20000, 5000),
+('Pewangi', 'ml', 10000, 3000),
+('Plastik', 'pcs', 80, 100),
+('Hanger', 'pcs', 200, 50),
+('Solvent Dry Clean', 'ml', 5000, 2000);
+
+-- Plastik is below its reorder level so it shows on the low stock report
+INSERT INTO stock_movement (supply_id, employee_id, change, stock_after, movement_type, note)
+VALUES
+(1, 1, 20000, 20000, 'restock', 'opening stock'),
+(2, 1, 10000, 10000, 'restock', 'opening stock'),
+(3, 1, 80, 80, 'restock', 'opening stock'),
+(4, 1, 200, 200, 'restock', 'opening stock'),
+(5, 1, 5000, 5000, 'restock', 'opening stock');
+
+-- Cuci Setrika per kg, Bed Cover, Dry Clean Jas per piece and Setrika Saja per kg
+INSERT INTO product_supply (product_id, supply_id, usage_per_unit)
+VALUES
+(6, 1, 30),
+(6, 2, 15),
+(7, 1, 100),
+(7, 2, 50),
+(7, 3, 1),
+(10, 5, 250),
+(10, 4, 1),
+(10, 3, 1),
+(11, 2, 5);
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+type JwtConfig struct {
+	Secret      []byte
+	Access_ttl  time.Duration
+	Refresh_ttl time.Duration
+}
+
+// Returned when env JWT_SECRET is empty, the app can not sign login tokens without it
+var ErrJwtSecretNotSet = errors.New("JWT_SECRET is not set in .env, set it to a random secret, ex : the output of openssl rand -hex 32")
+
+// Signing secret and token lifetime from env JWT_SECRET, JWT_ACCESS_TTL
+// (default 15m) and JWT_REFRESH_TTL (default 168h), read once at startup
+func LoadJwtConfig() (JwtConfig, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return JwtConfig{}, ErrJwtSecretNotSet
+	}
+
+	accessTtl, err := durationEnv("JWT_ACCESS_TTL", 15*time.Minute)
+	if err != nil {
+		return JwtConfig{}, err
+	}
+	refreshTtl, err := durationEnv("JWT_REFRESH_TTL", 7*24*time.Hour)
+	if err != nil {
+		return JwtConfig{}, err
+	}
+
+	return JwtConfig{
+		Secret:      []byte(secret),
+		Access_ttl:  accessTtl,
+		Refresh_ttl: refreshTtl,
+	}, nil
+}
+
+// A lifetime must be a positive Go duration, ex : 15m or 168h
+func durationEnv(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration, ex : 15m or 168h, got %q", key, value)
+	}
+	return duration, nil
+}
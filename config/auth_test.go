@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoadJwtConfig(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret")
+	t.Setenv("JWT_ACCESS_TTL", "")
+	t.Setenv("JWT_REFRESH_TTL", "24h")
+
+	jwtConfig, err := LoadJwtConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jwtConfig.Access_ttl != 15*time.Minute || jwtConfig.Refresh_ttl != 24*time.Hour {
+		t.Fatalf("got access ttl %s and refresh ttl %s", jwtConfig.Access_ttl, jwtConfig.Refresh_ttl)
+	}
+}
+
+func TestLoadJwtConfigRejects(t *testing.T) {
+	tests := []struct {
+		name       string
+		secret     string
+		accessTtl  string
+		refreshTtl string
+	}{
+		{"no secret", "", "", ""},
+		{"access ttl without unit", "secret", "15", ""},
+		{"access ttl not a duration", "secret", "fifteen minutes", ""},
+		{"zero access ttl", "secret", "0s", ""},
+		{"negative refresh ttl", "secret", "", "-1h"},
+		{"refresh ttl in days", "secret", "", "7d"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("JWT_SECRET", test.secret)
+			t.Setenv("JWT_ACCESS_TTL", test.accessTtl)
+			t.Setenv("JWT_REFRESH_TTL", test.refreshTtl)
+
+			_, err := LoadJwtConfig()
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if test.secret == "" && !errors.Is(err, ErrJwtSecretNotSet) {
+				t.Fatalf("expected %v, got %v", ErrJwtSecretNotSet, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// Legacy layout used by the first version of the API
+const LegacyDateLayout = "02-01-2006"
+
+// Layout of bill, entry and finish date in JSON responses. Set DATE_FORMAT
+// to "legacy" (dd-mm-yyyy, the default), "iso" or any Go time layout.
+func DateFormat() (dateLayout string, dateTimeLayout string) {
+	switch format := os.Getenv("DATE_FORMAT"); format {
+	case "", "legacy":
+		return LegacyDateLayout, LegacyDateLayout
+	case "iso":
+		return time.DateOnly, time.RFC3339
+	default:
+		return format, format
+	}
+}
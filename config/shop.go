@@ -0,0 +1,41 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+type ShopInfo struct {
+	Name      string
+	Address   string
+	Phone     string
+	Logo_path string
+}
+
+// Shop header printed on receipts, from env SHOP_NAME, SHOP_ADDRESS, SHOP_PHONE and SHOP_LOGO
+func LoadShopInfo() ShopInfo {
+	return ShopInfo{
+		Name:      stringEnv("SHOP_NAME", "Enigma Laundry"),
+		Address:   os.Getenv("SHOP_ADDRESS"),
+		Phone:     os.Getenv("SHOP_PHONE"),
+		Logo_path: stringEnv("SHOP_LOGO", "asset/Enigma-Laundry.png"),
+	}
+}
+
+// Branch part of bill numbers from env BRANCH_CODE, ex : JKT in EL-JKT-20261018-0042.
+// At most 10 letters or digits, it is kept in bill_sequence and a bill number is split on -
+func BranchCode() string {
+	branchCode := strings.ToUpper(stringEnv("BRANCH_CODE", "JKT"))
+	if len(branchCode) > 10 || strings.Trim(branchCode, "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789") != "" {
+		panic("BRANCH_CODE must be 1 to 10 letters or digits, ex : JKT")
+	}
+	return branchCode
+}
+
+func stringEnv(key string, fallback string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	return value
+}
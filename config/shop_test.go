@@ -0,0 +1,36 @@
+package config
+
+import "testing"
+
+func TestBranchCode(t *testing.T) {
+	tests := []struct {
+		env      string
+		expected string
+	}{
+		{"", "JKT"},
+		{"jkt", "JKT"},
+		{"BDG2", "BDG2"},
+		{"ABCDEFGHIJ", "ABCDEFGHIJ"},
+	}
+
+	for _, test := range tests {
+		t.Setenv("BRANCH_CODE", test.env)
+		if got := BranchCode(); got != test.expected {
+			t.Fatalf("%q expected %q, got %q", test.env, test.expected, got)
+		}
+	}
+}
+
+func TestBranchCodeRejects(t *testing.T) {
+	for _, env := range []string{"JKT-1", "ABCDEFGHIJK", "JK T", "JKT_1", "BDGÉ", "JKT\n"} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv("BRANCH_CODE", env)
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("%q expected a panic", env)
+				}
+			}()
+			BranchCode()
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package config
+
+import "os"
+
+// Supplies of a bill are taken from stock when the bill is made, or when it is ready
+// with env STOCK_DEDUCTION=finished
+func StockDeductedOnFinish() bool {
+	switch os.Getenv("STOCK_DEDUCTION") {
+	case "", "created":
+		return false
+	case "finished":
+		return true
+	}
+	panic("STOCK_DEDUCTION must be created or finished")
+}
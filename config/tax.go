@@ -0,0 +1,22 @@
+package config
+
+import (
+	"math"
+	"os"
+	"strconv"
+)
+
+// PPN rate in basis points from env TAX_RATE in percent, ex : TAX_RATE=11 is 1100.
+// Unset means no tax
+func TaxRate() int {
+	value := os.Getenv("TAX_RATE")
+	if value == "" {
+		return 0
+	}
+
+	percent, err := strconv.ParseFloat(value, 64)
+	if err != nil || percent < 0 || percent > 100 {
+		panic("TAX_RATE must be a percent between 0 and 100")
+	}
+	return int(math.Round(percent * 100))
+}
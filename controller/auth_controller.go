@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"submission-project-enigma-laundry/config"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/repository"
+	"submission-project-enigma-laundry/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type AuthController interface {
+	Login(ctx *gin.Context)
+	Refresh(ctx *gin.Context)
+}
+
+type authController struct {
+	employeeRepository repository.EmployeeRepository
+	jwtConfig          config.JwtConfig
+}
+
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+type AuthResponse struct {
+	Message string          `json:"message"`
+	Data    utils.TokenPair `json:"data"`
+}
+
+func NewAuthController(repo repository.EmployeeRepository, jwtConfig config.JwtConfig) AuthController {
+	return &authController{employeeRepository: repo, jwtConfig: jwtConfig}
+}
+
+func (ac *authController) Login(ctx *gin.Context) {
+	var request LoginRequest
+	err := ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	employee := entity.Employee{}
+
+	// Same answer for unknown username and wrong password
+	_, err = ac.employeeRepository.GetEmployeeByUsername(request.Username, &employee)
+	if err != nil || !utils.CheckPassword(employee.Password_hash, request.Password) {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"message" : "Invalid username or password"})
+		return
+	}
+
+	ac.issueTokens(ctx, &employee, "Successfully Login")
+}
+
+func (ac *authController) Refresh(ctx *gin.Context) {
+	var request RefreshRequest
+	err := ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	claims, err := utils.ParseToken(ac.jwtConfig.Secret, request.RefreshToken, utils.Token_refresh)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"message" : "Unauthorized", "details" : err.Error()})
+		return
+	}
+
+	// Employee may be deleted or have other roles since the refresh token was issued
+	employee := entity.Employee{}
+	isEmployeeExist, err := ac.employeeRepository.IsEmployeeExist(claims.Employee_id, &employee)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Employee", "details" : err.Error()})
+		return
+	}
+	if !isEmployeeExist {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"message" : "Unauthorized", "details" : "employee not found"})
+		return
+	}
+
+	_, err = ac.employeeRepository.GetDetailEmployee(claims.Employee_id, &employee)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get detail employee data", "details" : err.Error()})
+		return
+	}
+
+	ac.issueTokens(ctx, &employee, "Successfully Refresh Token")
+}
+
+func (ac *authController) issueTokens(ctx *gin.Context, employee *entity.Employee, message string) {
+	id, err := strconv.Atoi(employee.Employee_id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed convert employee id", "details" : err.Error()})
+		return
+	}
+
+	tokens, err := utils.GenerateTokenPair(ac.jwtConfig, id, employee.Roles)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to create token", "details" : err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, AuthResponse{Message: message, Data: tokens})
+}
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryController interface {
+	ListCategory(ctx *gin.Context)
+	CreateCategory(ctx *gin.Context)
+	UpdateCategory(ctx *gin.Context)
+	GetCatalogue(ctx *gin.Context)
+}
+
+type categoryController struct {
+	categoryRepository repository.CategoryRepository
+}
+
+type CategoryResponse struct {
+	Message string `json:"message"`
+	Data entity.Category `json:"data"`
+}
+
+type CategoryResponseSlice struct {
+	Message string `json:"message"`
+	Data []entity.Category `json:"data"`
+}
+
+func NewCategoryController(repo repository.CategoryRepository) CategoryController {
+	return &categoryController{categoryRepository: repo}
+}
+
+func (cc *categoryController) ListCategory(ctx *gin.Context) {
+	categories, err := cc.categoryRepository.GetCategories()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get categories", "details" : err.Error()})
+		return
+	}
+
+	response := CategoryResponseSlice{
+		Message: "Successfully get all categories",
+		Data: categories,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (cc *categoryController) CreateCategory(ctx *gin.Context) {
+	// A new category is shown on the catalogue unless active is false
+	newCategory := entity.Category{Active: true}
+	err := ctx.ShouldBind(&newCategory)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	newCategory.Name = strings.TrimSpace(newCategory.Name)
+	if newCategory.Name == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : "name is required"})
+		return
+	}
+	newCategory.Products = nil
+
+	createdCategory, err := cc.categoryRepository.CreateCategory(&newCategory)
+	if err != nil {
+		if errors.Is(err, repository.ErrCategoryNameTaken) {
+			ctx.JSON(http.StatusConflict, gin.H{"message" : "Category already exists", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to create category", "details" : err.Error()})
+		return
+	}
+
+	response := CategoryResponse{
+		Message: "Successfully Create Category",
+		Data: *createdCategory,
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (cc *categoryController) UpdateCategory(ctx *gin.Context) {
+	convertedId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
+		return
+	}
+
+	category, err := cc.categoryRepository.GetCategory(convertedId)
+	if err != nil {
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message" : "category not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get category", "details" : err.Error()})
+		return
+	}
+
+	var request struct {
+		Name        string  `json:"name"`
+		Description *string `json:"description"`
+		Sort_order  *int    `json:"sortOrder"`
+		Active      *bool   `json:"active"`
+	}
+	err = ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	if strings.TrimSpace(request.Name) != "" {
+		category.Name = strings.TrimSpace(request.Name)
+	}
+	if request.Description != nil {
+		category.Description = *request.Description
+	}
+	if request.Sort_order != nil {
+		category.Sort_order = *request.Sort_order
+	}
+	if request.Active != nil {
+		category.Active = *request.Active
+	}
+
+	updatedCategory, err := cc.categoryRepository.UpdateCategory(convertedId, category)
+	if err != nil {
+		if errors.Is(err, repository.ErrCategoryNameTaken) {
+			ctx.JSON(http.StatusConflict, gin.H{"message" : "Category already exists", "details" : err.Error()})
+			return
+		}
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message" : "category not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to update category", "details" : err.Error()})
+		return
+	}
+
+	response := CategoryResponse{
+		Message: "Successfully Updated Category",
+		Data: *updatedCategory,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Active categories with their active products, for the counter
+func (cc *categoryController) GetCatalogue(ctx *gin.Context) {
+	catalogue, err := cc.categoryRepository.GetCatalogue()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get catalogue", "details" : err.Error()})
+		return
+	}
+
+	response := CategoryResponseSlice{
+		Message: "Successfully get catalogue",
+		Data: catalogue,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -1,221 +1,786 @@
-package controller
-
-import (
-	"net/http"
-	"strconv"
-	"strings"
-	"submission-project-enigma-laundry/entity"
-	"submission-project-enigma-laundry/repository"
-	"github.com/gin-gonic/gin"
-)
-
-type CustomerController interface {
-	CreateCustomer(ctx *gin.Context)
-	GetAllCustomer(ctx *gin.Context)
-	GetDetailCustomer(ctx *gin.Context)
-	UpdateCustomer(ctx *gin.Context)
-	DeleteCustomer(ctx *gin.Context)
-}
-
-type CustomerResponse struct {
-	Message string `json:"message"`
-	Data  entity.Customer `json:"data"`
-}
-
-type CustomerResponseSlice struct {
-	Message string `json:"message"`
-	Data []entity.Customer `json:"data"`
-}
-
-type customerController struct {
-	CustomerRepository repository.CustomerRepository
-}
-
-func NewCustomerController(repo repository.CustomerRepository) CustomerController {
-	return &customerController{CustomerRepository: repo}
-}
-
-func (cc *customerController) CreateCustomer(ctx *gin.Context) {
-	var newCustomer entity.Customer
-	err := ctx.ShouldBind(&newCustomer)
-
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
-		return
-	}
-
-	createdCustomer,err := cc.CustomerRepository.CreateCustomer(&newCustomer)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to create customer", "details" : err.Error()})
-		return
-	}
-	// Create The response struct 
-	response := CustomerResponse{
-		Message: "Successfuly Create Customer",
-		Data: *createdCustomer,
-	}
-
-	ctx.JSON(http.StatusCreated, response)
-}
-
-func (cc *customerController) GetAllCustomer(ctx *gin.Context) {
-	customers := []entity.Customer{}
-	rows, err := cc.CustomerRepository.GetCustomer()
-	
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get all customer data", "details" : err.Error()})
-		return
-	}
-
-	defer rows.Close()
-
-	for rows.Next() {
-		customer := entity.Customer{}
-		err = rows.Scan(&customer.Customer_id,&customer.Name,&customer.Phone_number,&customer.Address)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed scanning customer data", "details" : err.Error()})
-			return
-		}
-		customers = append(customers, customer)
-	}
-
-	err = rows.Err()
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error encountred during iteration", "details" : err.Error()})
-		return
-	}
-
-	response := CustomerResponseSlice{
-		Message: "Successfully get all data from customer",
-		Data: customers,
-	}
-
-	ctx.JSON(http.StatusOK, response)
-}
-
-func (cc *customerController) GetDetailCustomer(ctx *gin.Context) {
-	id := ctx.Param("id")
-	
-	convertedId,err := strconv.Atoi(id)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Failed convert id. Make sure id is number", "details" : err.Error()})
-		return
-	}
-
-	customer := entity.Customer{}
-
-	detailCustomer, err := cc.CustomerRepository.GetDetailCustomer(convertedId,&customer)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get detail customer data", "details" : err.Error()})
-		return
-	}
-
-	// Create The response struct 
-	response := CustomerResponse{
-		Message: "Successfuly Get Customer Detail",
-		Data: *detailCustomer,
-	}
-	
-	ctx.JSON(http.StatusOK, response)
-}
-
-func (cc *customerController) UpdateCustomer(ctx *gin.Context) {
-	id := ctx.Param("id")
-  
-	convertedId, err := strconv.Atoi(id)
-	if err != nil {
-	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
-	  return
-	}
-  
-	customer := entity.Customer{}
-  
-	detailCustomer, err := cc.CustomerRepository.GetDetailCustomer(convertedId, &customer)
-	if err != nil {
-	  ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to get customer data", "details": err.Error()})
-	  return
-	}
-
-	updateCustomer := entity.Customer{}
-  
-	err = ctx.ShouldBind(&updateCustomer)
-	if err != nil {
-	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid Input", "details": err.Error()})
-	  return
-	}
-  
-	// Update existing customer data
-	if strings.TrimSpace(updateCustomer.Name) != "" {
-	  detailCustomer.Name = updateCustomer.Name
-	}
-	if strings.TrimSpace(updateCustomer.Phone_number) != "" {
-	  detailCustomer.Phone_number = updateCustomer.Phone_number
-	}
-	if strings.TrimSpace(updateCustomer.Address) != "" {
-	  detailCustomer.Address = updateCustomer.Address
-	}
-  
-	updatedCustomer, err := cc.CustomerRepository.UpdateCustomer(convertedId,detailCustomer) // Assuming UpdateCustomer function exists
-	if err != nil {
-	  ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update customer data", "details": err.Error()})
-	  return
-	}
-  
-	// Create The response struct 
-	response := CustomerResponse{
-	  Message: "Successfully Updated Customer Data",
-	  Data: *updatedCustomer,
-	}
-  
-	ctx.JSON(http.StatusOK, response) // Updated status code to 200 (OK)
-  }
-
-func (cc *customerController) DeleteCustomer(ctx *gin.Context) {
-	id := ctx.Param("id")
-  
-	convertedId, err := strconv.Atoi(id)
-	if err != nil {
-	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
-	  return
-	}
-
-	customer := entity.Customer{}
-
-	isCustomerExist,err := cc.CustomerRepository.IsCustomerExist(convertedId,&customer)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Customer", "details" : err.Error()})
-		return
-	}
-	if !isCustomerExist {
-		ctx.JSON(http.StatusNotFound, gin.H{"message" : "customer not found"})
-		return
-	}
-
-	transaction := entity.Transaction{}
-
-	isCustomerInTransaction,err := cc.CustomerRepository.CustomerInTransaction(convertedId,&transaction)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError,gin.H{"message" : "Error While Checking Customer in Transaction", "details" : err.Error()})
-		return
-	} 
-	if isCustomerInTransaction {
-		ctx.JSON(http.StatusConflict,gin.H{"message" : "Customer is being used in transaction. Please delete the transaction first"})
-		return
-	}
-
-	_,err = cc.CustomerRepository.DeleteCustomer(convertedId)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error Deleting Customer", "details" :err.Error()})
-		return
-	}
-
-	response := struct {
-		Message string `json:"message"`
-		Data string `json:"data"`
-	}{
-		Message: "Successfully deleted data",
-		Data: "OK",
-	}
-
-	ctx.JSON(http.StatusOK,response)
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"submission-project-enigma-laundry/config"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/repository"
+	"submission-project-enigma-laundry/utils"
+	"time"
+	"github.com/gin-gonic/gin"
+)
+
+type CustomerController interface {
+	CreateCustomer(ctx *gin.Context)
+	GetAllCustomer(ctx *gin.Context)
+	GetDetailCustomer(ctx *gin.Context)
+	UpdateCustomer(ctx *gin.Context)
+	DeleteCustomer(ctx *gin.Context)
+	RestoreCustomer(ctx *gin.Context)
+	GetCustomerPoints(ctx *gin.Context)
+	GetCustomerWallet(ctx *gin.Context)
+	TopUpWallet(ctx *gin.Context)
+	GetCustomerSubscriptions(ctx *gin.Context)
+	CreateSubscription(ctx *gin.Context)
+	GetCustomerTransactions(ctx *gin.Context)
+	MergeCustomer(ctx *gin.Context)
+}
+
+type CustomerResponse struct {
+	Message string `json:"message"`
+	Data  entity.Customer `json:"data"`
+}
+
+type CustomerResponseSlice = PagedResponse[entity.Customer]
+
+// Customer detail with the remaining quota of its active subscriptions
+type CustomerDetailResponse struct {
+	Message string `json:"message"`
+	Data struct {
+		entity.Customer
+		Subscriptions []SubscriptionData `json:"subscriptions"`
+	} `json:"data"`
+}
+
+type SubscriptionData struct {
+	Id                string   `json:"id"`
+	CustomerId        string   `json:"customerId"`
+	PackageId         string   `json:"packageId"`
+	PackageName       string   `json:"packageName"`
+	StartDate         string   `json:"startDate"`
+	EndDate           string   `json:"endDate"`
+	Quota             float64  `json:"quota"`
+	Used              float64  `json:"used"`
+	Remaining         float64  `json:"remaining"`
+	Price             int      `json:"price"`
+	CoveredProductIds []string `json:"coveredProductIds"`
+	Active            bool     `json:"active"`
+}
+
+type SubscriptionResponse struct {
+	Message string `json:"message"`
+	Data SubscriptionData `json:"data"`
+}
+
+type SubscriptionResponseSlice struct {
+	Message string `json:"message"`
+	Data []SubscriptionData `json:"data"`
+}
+
+// Bills of a customer, one page at a time, with the lifetime summary of the customer
+type CustomerTransactionsResponse struct {
+	Message string `json:"message"`
+	Data []TransactionDataResponse `json:"data"`
+	Summary entity.Customer_summary `json:"summary"`
+	Paging entity.Paging `json:"paging"`
+}
+
+// The duplicate customer is merged into the customer of the url and deleted
+type MergeCustomerRequest struct {
+	CustomerId string `json:"customerId"`
+}
+
+type MergeCustomerResponse struct {
+	Message string `json:"message"`
+	Data entity.Customer `json:"data"`
+	MergedTransactions int `json:"mergedTransactions"`
+}
+
+// Without endDate the subscription lasts one month
+type SubscriptionRequest struct {
+	PackageId string `json:"packageId"`
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+}
+
+type CustomerPointsResponse struct {
+	Message string `json:"message"`
+	Data entity.Customer_points `json:"data"`
+}
+
+type CustomerWalletResponse struct {
+	Message string `json:"message"`
+	Data entity.Customer_wallet `json:"data"`
+}
+
+type WalletEntryResponse struct {
+	Message string `json:"message"`
+	Data entity.Wallet_entry `json:"data"`
+}
+
+type TopUpRequest struct {
+	Amount int    `json:"amount"`
+	Method string `json:"method"`
+	Note   string `json:"note"`
+}
+
+type customerController struct {
+	CustomerRepository repository.CustomerRepository
+	PointRepository repository.PointRepository
+	WalletRepository repository.WalletRepository
+	SubscriptionRepository repository.SubscriptionRepository
+	TransactionRepository repository.TransactionRepository
+}
+
+func NewCustomerController(repo repository.CustomerRepository, pointRepo repository.PointRepository, walletRepo repository.WalletRepository, subscriptionRepo repository.SubscriptionRepository, transactionRepo repository.TransactionRepository) CustomerController {
+	return &customerController{CustomerRepository: repo, PointRepository: pointRepo, WalletRepository: walletRepo, SubscriptionRepository: subscriptionRepo, TransactionRepository: transactionRepo}
+}
+
+func (cc *customerController) CreateCustomer(ctx *gin.Context) {
+	var newCustomer entity.Customer
+	err := ctx.ShouldBind(&newCustomer)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	newCustomer.Phone_number, err = utils.NormalizePhoneNumber(newCustomer.Phone_number)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+	if cc.phoneNumberTaken(ctx, newCustomer.Phone_number, 0) {
+		return
+	}
+
+	createdCustomer,err := cc.CustomerRepository.CreateCustomer(&newCustomer)
+	if err != nil {
+		// Another request registered the number after the check
+		if errors.Is(err, repository.ErrPhoneNumberTaken) && cc.phoneNumberTaken(ctx, newCustomer.Phone_number, 0) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to create customer", "details" : err.Error()})
+		return
+	}
+	// Create The response struct 
+	response := CustomerResponse{
+		Message: "Successfuly Create Customer",
+		Data: *createdCustomer,
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (cc *customerController) GetAllCustomer(ctx *gin.Context) {
+	customers := []entity.Customer{}
+
+	page, err := parsePageRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid paging", "details" : err.Error()})
+		return
+	}
+
+	filter, err := parseListFilter(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input", "details" : err.Error()})
+		return
+	}
+
+	rows, total, err := cc.CustomerRepository.GetCustomer(filter, page)
+	
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidPaging) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid paging", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get all customer data", "details" : err.Error()})
+		return
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		customer := entity.Customer{}
+		err = rows.Scan(&customer.Customer_id,&customer.Name,&customer.Phone_number,&customer.Address,&customer.Deleted_at)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed scanning customer data", "details" : err.Error()})
+			return
+		}
+		customers = append(customers, customer)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error encountred during iteration", "details" : err.Error()})
+		return
+	}
+
+	customers, paging := pageOf(page, total, customers, func(customer entity.Customer) string { return customer.Customer_id })
+
+	response := CustomerResponseSlice{
+		Message: "Successfully get all data from customer",
+		Data: customers,
+		Paging: paging,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (cc *customerController) GetDetailCustomer(ctx *gin.Context) {
+	id := ctx.Param("id")
+	
+	convertedId,err := strconv.Atoi(id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Failed convert id. Make sure id is number", "details" : err.Error()})
+		return
+	}
+
+	customer := entity.Customer{}
+
+	detailCustomer, err := cc.CustomerRepository.GetDetailCustomer(convertedId,&customer)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get detail customer data", "details" : err.Error()})
+		return
+	}
+
+	subscriptions, err := cc.SubscriptionRepository.GetSubscriptions(convertedId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get customer subscriptions", "details" : err.Error()})
+		return
+	}
+
+	// Create The response struct 
+	var response CustomerDetailResponse
+	response.Message = "Successfuly Get Customer Detail"
+	response.Data.Customer = *detailCustomer
+	response.Data.Subscriptions = []SubscriptionData{}
+	for _, subscription := range subscriptions {
+		if subscription.IsActive(time.Now()) {
+			response.Data.Subscriptions = append(response.Data.Subscriptions, newSubscriptionData(subscription))
+		}
+	}
+	
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (cc *customerController) UpdateCustomer(ctx *gin.Context) {
+	id := ctx.Param("id")
+  
+	convertedId, err := strconv.Atoi(id)
+	if err != nil {
+	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
+	  return
+	}
+  
+	customer := entity.Customer{}
+  
+	detailCustomer, err := cc.CustomerRepository.GetDetailCustomer(convertedId, &customer)
+	if err != nil {
+	  ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to get customer data", "details": err.Error()})
+	  return
+	}
+
+	updateCustomer := entity.Customer{}
+  
+	err = ctx.ShouldBind(&updateCustomer)
+	if err != nil {
+	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid Input", "details": err.Error()})
+	  return
+	}
+  
+	// Update existing customer data
+	if strings.TrimSpace(updateCustomer.Name) != "" {
+	  detailCustomer.Name = updateCustomer.Name
+	}
+	if strings.TrimSpace(updateCustomer.Phone_number) != "" {
+	  detailCustomer.Phone_number, err = utils.NormalizePhoneNumber(updateCustomer.Phone_number)
+	  if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid Input", "details": err.Error()})
+		return
+	  }
+	  if cc.phoneNumberTaken(ctx, detailCustomer.Phone_number, convertedId) {
+		return
+	  }
+	}
+	if strings.TrimSpace(updateCustomer.Address) != "" {
+	  detailCustomer.Address = updateCustomer.Address
+	}
+  
+	updatedCustomer, err := cc.CustomerRepository.UpdateCustomer(convertedId,detailCustomer) // Assuming UpdateCustomer function exists
+	if err != nil {
+	  if errors.Is(err, repository.ErrPhoneNumberTaken) && cc.phoneNumberTaken(ctx, detailCustomer.Phone_number, convertedId) {
+		return
+	  }
+	  ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update customer data", "details": err.Error()})
+	  return
+	}
+  
+	// Create The response struct 
+	response := CustomerResponse{
+	  Message: "Successfully Updated Customer Data",
+	  Data: *updatedCustomer,
+	}
+  
+	ctx.JSON(http.StatusOK, response) // Updated status code to 200 (OK)
+  }
+
+func (cc *customerController) DeleteCustomer(ctx *gin.Context) {
+	id := ctx.Param("id")
+  
+	convertedId, err := strconv.Atoi(id)
+	if err != nil {
+	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
+	  return
+	}
+
+	customer := entity.Customer{}
+
+	isCustomerExist,err := cc.CustomerRepository.IsCustomerExist(convertedId,&customer)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Customer", "details" : err.Error()})
+		return
+	}
+	if !isCustomerExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "customer not found"})
+		return
+	}
+
+	_,err = cc.CustomerRepository.DeleteCustomer(convertedId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error Deleting Customer", "details" :err.Error()})
+		return
+	}
+
+	response := struct {
+		Message string `json:"message"`
+		Data string `json:"data"`
+	}{
+		Message: "Successfully deleted data",
+		Data: "OK",
+	}
+
+	ctx.JSON(http.StatusOK,response)
+}
+
+func (cc *customerController) RestoreCustomer(ctx *gin.Context) {
+	convertedId,err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Failed convert id. Make sure id is number", "details" : err.Error()})
+		return
+	}
+
+	isRestored,err := cc.CustomerRepository.RestoreCustomer(convertedId)
+	if err != nil {
+		// Another customer was registered with the phone number after it was deleted
+		if errors.Is(err, repository.ErrPhoneNumberTaken) {
+			deleted := entity.Customer{}
+			_, detailErr := cc.CustomerRepository.GetDetailCustomer(convertedId,&deleted)
+			if detailErr == nil && cc.phoneNumberTaken(ctx, deleted.Phone_number, convertedId) {
+				return
+			}
+			ctx.JSON(http.StatusConflict, gin.H{"message" : "Customer already registered", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error Restoring Customer", "details" : err.Error()})
+		return
+	}
+	if !isRestored {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "customer not found"})
+		return
+	}
+
+	customer := entity.Customer{}
+
+	restoredCustomer,err := cc.CustomerRepository.GetDetailCustomer(convertedId,&customer)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get detail customer data", "details" : err.Error()})
+		return
+	}
+
+	response := CustomerResponse{
+		Message: "Successfully Restored Customer",
+		Data: *restoredCustomer,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Points balance, membership tier and the points ledger, newest first
+func (cc *customerController) GetCustomerPoints(ctx *gin.Context) {
+	convertedId,err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Failed convert id. Make sure id is number", "details" : err.Error()})
+		return
+	}
+
+	customer := entity.Customer{}
+
+	isCustomerExist,err := cc.CustomerRepository.IsCustomerExist(convertedId,&customer)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Customer", "details" : err.Error()})
+		return
+	}
+	if !isCustomerExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "customer not found"})
+		return
+	}
+
+	points,err := cc.PointRepository.GetCustomerPoints(convertedId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get customer points", "details" : err.Error()})
+		return
+	}
+
+	response := CustomerPointsResponse{
+		Message: "Successfully Get Customer Points",
+		Data: *points,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Wallet balance and its statement, newest first
+func (cc *customerController) GetCustomerWallet(ctx *gin.Context) {
+	convertedId,err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Failed convert id. Make sure id is number", "details" : err.Error()})
+		return
+	}
+
+	customer := entity.Customer{}
+
+	isCustomerExist,err := cc.CustomerRepository.IsCustomerExist(convertedId,&customer)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Customer", "details" : err.Error()})
+		return
+	}
+	if !isCustomerExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "customer not found"})
+		return
+	}
+
+	wallet,err := cc.WalletRepository.GetWallet(convertedId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get customer wallet", "details" : err.Error()})
+		return
+	}
+
+	response := CustomerWalletResponse{
+		Message: "Successfully Get Customer Wallet",
+		Data: *wallet,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (cc *customerController) TopUpWallet(ctx *gin.Context) {
+	convertedId,err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Failed convert id. Make sure id is number", "details" : err.Error()})
+		return
+	}
+
+	var request TopUpRequest
+	err = ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	if request.Amount <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid top up", "details": "amount must be greater than 0"})
+		return
+	}
+	if !entity.IsValidTopUpMethod(request.Method) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid top up", "details": "method must be one of cash, transfer, e_wallet"})
+		return
+	}
+
+	customer := entity.Customer{}
+
+	isCustomerExist,err := cc.CustomerRepository.IsCustomerExist(convertedId,&customer)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Customer", "details" : err.Error()})
+		return
+	}
+	if !isCustomerExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "customer not found"})
+		return
+	}
+
+	entry := entity.Wallet_entry{Amount: request.Amount, Method: request.Method, Note: strings.TrimSpace(request.Note)}
+	createdEntry,err := cc.WalletRepository.TopUp(convertedId, &entry)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to top up wallet", "details" : err.Error()})
+		return
+	}
+
+	response := WalletEntryResponse{
+		Message: "Successfully Top Up Wallet",
+		Data: *createdEntry,
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// Every subscription of the customer, the latest first
+func (cc *customerController) GetCustomerSubscriptions(ctx *gin.Context) {
+	convertedId,err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Failed convert id. Make sure id is number", "details" : err.Error()})
+		return
+	}
+
+	customer := entity.Customer{}
+
+	isCustomerExist,err := cc.CustomerRepository.IsCustomerExist(convertedId,&customer)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Customer", "details" : err.Error()})
+		return
+	}
+	if !isCustomerExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "customer not found"})
+		return
+	}
+
+	subscriptions,err := cc.SubscriptionRepository.GetSubscriptions(convertedId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get customer subscriptions", "details" : err.Error()})
+		return
+	}
+
+	response := SubscriptionResponseSlice{
+		Message: "Successfully Get Customer Subscriptions",
+		Data: []SubscriptionData{},
+	}
+	for _, subscription := range subscriptions {
+		response.Data = append(response.Data, newSubscriptionData(subscription))
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (cc *customerController) CreateSubscription(ctx *gin.Context) {
+	convertedId,err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Failed convert id. Make sure id is number", "details" : err.Error()})
+		return
+	}
+
+	var request SubscriptionRequest
+	err = ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	subscription := entity.Subscription{Customer_id: strconv.Itoa(convertedId), Package_id: strings.TrimSpace(request.PackageId)}
+	_, err = strconv.Atoi(subscription.Package_id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Failed convert package id. Make sure packageId is number", "details" : err.Error()})
+		return
+	}
+
+	subscription.Start_date, err = parseDate(request.StartDate)
+	if err == nil {
+		subscription.End_date = subscription.Start_date.AddDate(0, 1, -1)
+		if strings.TrimSpace(request.EndDate) != "" {
+			subscription.End_date, err = parseDate(request.EndDate)
+		}
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid date", "details" : err.Error()})
+		return
+	}
+	if subscription.End_date.Before(subscription.Start_date) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid date", "details" : "endDate can not be before startDate"})
+		return
+	}
+
+	customer := entity.Customer{}
+
+	isCustomerExist,err := cc.CustomerRepository.IsCustomerExist(convertedId,&customer)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Customer", "details" : err.Error()})
+		return
+	}
+	if !isCustomerExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "customer not found"})
+		return
+	}
+
+	createdSubscription,err := cc.SubscriptionRepository.CreateSubscription(&subscription)
+	if err != nil {
+		if errors.Is(err, repository.ErrPackageNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message" : "package not found", "details" : err.Error()})
+			return
+		}
+		if errors.Is(err, repository.ErrWalletInsufficient) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Wallet balance is not enough", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to create subscription", "details" : err.Error()})
+		return
+	}
+
+	response := SubscriptionResponse{
+		Message: "Successfully Create Subscription",
+		Data: newSubscriptionData(*createdSubscription),
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// Bills of the customer with the same paging, sort and date filters as List Transaction
+func (cc *customerController) GetCustomerTransactions(ctx *gin.Context) {
+	convertedId,err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Failed convert id. Make sure id is number", "details" : err.Error()})
+		return
+	}
+
+	page, err := parsePageRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid paging", "details" : err.Error()})
+		return
+	}
+
+	filter := repository.TransactionFilter{CustomerId: convertedId, Status: ctx.Query("status")}
+	if !parseDateFilter(ctx, &filter) {
+		return
+	}
+	if filter.Status != "" && !entity.IsValidStatus(filter.Status) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid status", "details": "status must be one of received, washing, ready, picked_up, cancelled"})
+		return
+	}
+
+	customer := entity.Customer{}
+
+	isCustomerExist,err := cc.CustomerRepository.IsCustomerExist(convertedId,&customer)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Customer", "details" : err.Error()})
+		return
+	}
+	if !isCustomerExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "customer not found"})
+		return
+	}
+
+	transactions,total,err := cc.TransactionRepository.ListTransaction(filter,page)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidPaging) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid paging", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get customer transactions", "details" : err.Error()})
+		return
+	}
+
+	summary,err := cc.CustomerRepository.GetCustomerSummary(convertedId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get customer summary", "details" : err.Error()})
+		return
+	}
+
+	transactions, paging := pageOf(page, total, transactions, func(transaction entity.Transaction) string { return transaction.Transaction_id })
+
+	response := CustomerTransactionsResponse{
+		Message: "Successfully Get Customer Transactions",
+		Data: []TransactionDataResponse{},
+		Summary: *summary,
+		Paging: paging,
+	}
+	for i := range transactions {
+		response.Data = append(response.Data, newTransactionDataResponse(&transactions[i]))
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Bills, points, wallet and subscriptions of the duplicate customer move to the customer, then the duplicate is deleted
+func (cc *customerController) MergeCustomer(ctx *gin.Context) {
+	convertedId,err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Failed convert id. Make sure id is number", "details" : err.Error()})
+		return
+	}
+
+	var request MergeCustomerRequest
+	err = ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	duplicateId,err := strconv.Atoi(request.CustomerId)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Failed convert customer id. Make sure customer id is number", "details" : err.Error()})
+		return
+	}
+	if duplicateId == convertedId {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : "a customer can not be merged into itself"})
+		return
+	}
+
+	customer := entity.Customer{}
+
+	isCustomerExist,err := cc.CustomerRepository.IsCustomerExist(convertedId,&customer)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Customer", "details" : err.Error()})
+		return
+	}
+	if !isCustomerExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "customer not found"})
+		return
+	}
+
+	merged,err := cc.CustomerRepository.MergeCustomer(convertedId,duplicateId)
+	if err != nil {
+		if errors.Is(err, repository.ErrCustomerNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message" : "customer not found", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to merge customer", "details" : err.Error()})
+		return
+	}
+
+	mergedCustomer,err := cc.CustomerRepository.GetDetailCustomer(convertedId,&customer)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get detail customer data", "details" : err.Error()})
+		return
+	}
+
+	response := MergeCustomerResponse{
+		Message: "Successfully Merged Customer",
+		Data: *mergedCustomer,
+		MergedTransactions: merged,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Write 409 with the customer that already has the phone number, other than the customer with exceptId
+func (cc *customerController) phoneNumberTaken(ctx *gin.Context, phoneNumber string, exceptId int) bool {
+	existing, found, err := cc.CustomerRepository.GetCustomerByPhoneNumber(phoneNumber)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Customer", "details" : err.Error()})
+		return true
+	}
+	if !found || existing.Customer_id == strconv.Itoa(exceptId) {
+		return false
+	}
+	ctx.JSON(http.StatusConflict, gin.H{"message" : "Customer already registered", "details" : "phone number " + phoneNumber + " belongs to customer " + existing.Customer_id, "data" : existing})
+	return true
+}
+
+func newSubscriptionData(subscription entity.Subscription) SubscriptionData {
+	dateLayout, _ := config.DateFormat()
+	return SubscriptionData{
+		Id:                subscription.Subscription_id,
+		CustomerId:        subscription.Customer_id,
+		PackageId:         subscription.Package_id,
+		PackageName:       subscription.Package_name,
+		StartDate:         subscription.Start_date.Format(dateLayout),
+		EndDate:           subscription.End_date.Format(dateLayout),
+		Quota:             subscription.Quota,
+		Used:              subscription.Used,
+		Remaining:         subscription.Remaining(),
+		Price:             subscription.Price,
+		CoveredProductIds: subscription.Covered_product_ids,
+		Active:            subscription.IsActive(time.Now()),
+	}
 }
\ No newline at end of file
@@ -1,217 +1,387 @@
-package controller
-
-import (
-	"net/http"
-	"strconv"
-	"strings"
-	"submission-project-enigma-laundry/entity"
-	"submission-project-enigma-laundry/repository"
-	"github.com/gin-gonic/gin"
-)
-
-type EmployeeController interface {
-	CreateEmployee(ctx *gin.Context)
-	GetAllEmployee(ctx *gin.Context)
-	GetDetailEmployee(ctx *gin.Context)
-	UpdateEmployee(ctx *gin.Context)
-	DeleteEmployee(ctx *gin.Context)
-}
-
-type employeeController struct {
-	employeeRepository repository.EmployeeRepository
-}
-
-type EmployeeResponse struct {
-	Message string `json:"message"`
-	Data entity.Employee `json:"data"`
-}
-
-type EmployeeResponseSlice struct {
-	Message string `json:"message"`
-	Data []entity.Employee `json:"data"`
-}
-
-func NewEmployeeController(repo repository.EmployeeRepository) EmployeeController {
-	return &employeeController{employeeRepository: repo}
-}
-
-func (ec *employeeController) CreateEmployee(ctx *gin.Context) {
-	var newEmployee entity.Employee
-	err := ctx.ShouldBind(&newEmployee)
-
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
-		return
-	}
-
-	createdEmployee,err := ec.employeeRepository.CreateEmployee(&newEmployee)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to create employee", "details" : err.Error()})
-		return
-	}
-	// Create The response struct 
-	response := EmployeeResponse{
-		Message: "Successfuly Create Employee",
-		Data: *createdEmployee,
-	}
-
-	ctx.JSON(http.StatusCreated, response)
-}
-
-func (ec *employeeController) GetAllEmployee(ctx *gin.Context) {
-	employees := []entity.Employee{}
-	rows, err := ec.employeeRepository.GetEmployee()
-	
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get all employee data", "details" : err.Error()})
-		return
-	}
-
-	defer rows.Close()
-
-	for rows.Next() {
-		employee := entity.Employee{}
-		err = rows.Scan(&employee.Employee_id,&employee.Name,&employee.Phone_number,&employee.Address)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed scanning employee data", "details" : err.Error()})
-			return
-		}
-		employees = append(employees, employee)
-	}
-
-	err = rows.Err()
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error encountred during iteration", "details" : err.Error()})
-		return
-	}
-
-	response :=EmployeeResponseSlice{
-		Message: "Successfully get all data from employee",
-		Data: employees,
-	}
-
-	ctx.JSON(http.StatusOK, response)
-}
-
-func (ec *employeeController) GetDetailEmployee(ctx *gin.Context) {
-	id,err := strconv.Atoi(ctx.Param("id"))
-
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Failed convert id. Make sure id is number", "details" : err.Error()})
-		return
-	}
-
-	employee := entity.Employee{}
-
-	detailEmployee, err := ec.employeeRepository.GetDetailEmployee(id,&employee)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get detail employee data", "details" : err.Error()})
-		return
-	}
-
-	// Create The response struct 
-	response := EmployeeResponse{
-		Message: "Successfuly Get Employee Detail",
-		Data: *detailEmployee,
-	}
-	
-	ctx.JSON(http.StatusOK, response)
-}
-
-func (ec *employeeController) UpdateEmployee(ctx *gin.Context) {
-	convertedId, err := strconv.Atoi(ctx.Param("id"))
-	
-	if err != nil {
-	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
-	  return
-	}
-  
-	employee := entity.Employee{}
-  
-	detailEmployee, err := ec.employeeRepository.GetDetailEmployee(convertedId, &employee)
-	if err != nil {
-	  ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to get employee data", "details": err.Error()})
-	  return
-	}
-
-	updateEmployee := entity.Employee{}
-  
-	err = ctx.ShouldBind(&updateEmployee)
-	if err != nil {
-	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid Input", "details": err.Error()})
-	  return
-	}
-  
-	// Update existing employee data
-	if strings.TrimSpace(updateEmployee.Name) != "" {
-	  detailEmployee.Name = updateEmployee.Name
-	}
-	if strings.TrimSpace(updateEmployee.Phone_number) != "" {
-	  detailEmployee.Phone_number = updateEmployee.Phone_number
-	}
-	if strings.TrimSpace(updateEmployee.Address) != "" {
-	  detailEmployee.Address = updateEmployee.Address
-	}
-  
-	updatedEmployee, err := ec.employeeRepository.UpdateEmployee(convertedId,detailEmployee) // Assuming updateEmployee function exists
-	if err != nil {
-	  ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update employee data", "details": err.Error()})
-	  return
-	}
-  
-	// Create The response struct 
-	response := EmployeeResponse{
-	  Message: "Successfully Updated Employee Data",
-	  Data: *updatedEmployee,
-	}
-  
-	ctx.JSON(http.StatusOK, response) // Updated status code to 200 (OK)
-  }
-
-  func (ec *employeeController) DeleteEmployee(ctx *gin.Context) {
-	convertedId, err := strconv.Atoi(ctx.Param("id"))
-	if err != nil {
-	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
-	  return
-	}
-
-	employee := entity.Employee{}
-
-	isEmployeeExist,err := ec.employeeRepository.IsEmployeeExist(convertedId,&employee)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Employee", "details" : err.Error()})
-		return
-	}
-	if !isEmployeeExist {
-		ctx.JSON(http.StatusNotFound, gin.H{"message" : "employee not found"})
-		return
-	}
-
-	transaction := entity.Transaction{}
-
-	isEmployeeInTransaction,err := ec.employeeRepository.EmployeeInTransaction(convertedId,&transaction)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError,gin.H{"message" : "Error While Checking Employee in Transaction", "details" : err.Error()})
-		return
-	} 
-	if isEmployeeInTransaction {
-		ctx.JSON(http.StatusConflict,gin.H{"message" : "Employee is being used in transaction. Please delete the transaction first"})
-		return
-	}
-
-	_,err = ec.employeeRepository.DeleteEmployee(convertedId)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error Deleting Employee", "details" :err.Error()})
-		return
-	}
-
-	response := struct {
-		Message string `json:"message"`
-		Data string `json:"data"`
-	}{
-		Message: "Successfully deleted data",
-		Data: "OK",
-	}
-
-	ctx.JSON(http.StatusOK,response)
-}
\ No newline at end of file
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/repository"
+	"submission-project-enigma-laundry/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+type EmployeeController interface {
+	CreateEmployee(ctx *gin.Context)
+	GetAllEmployee(ctx *gin.Context)
+	GetDetailEmployee(ctx *gin.Context)
+	UpdateEmployee(ctx *gin.Context)
+	DeleteEmployee(ctx *gin.Context)
+	RestoreEmployee(ctx *gin.Context)
+	AssignRole(ctx *gin.Context)
+	RevokeRole(ctx *gin.Context)
+}
+
+type employeeController struct {
+	employeeRepository repository.EmployeeRepository
+}
+
+type EmployeeResponse struct {
+	Message string `json:"message"`
+	Data entity.Employee `json:"data"`
+}
+
+type EmployeeResponseSlice = PagedResponse[entity.Employee]
+
+func NewEmployeeController(repo repository.EmployeeRepository) EmployeeController {
+	return &employeeController{employeeRepository: repo}
+}
+
+func (ec *employeeController) CreateEmployee(ctx *gin.Context) {
+	var newEmployee entity.Employee
+	err := ctx.ShouldBind(&newEmployee)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	if strings.TrimSpace(newEmployee.Username) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : "username is required"})
+		return
+	}
+	err = hashEmployeePassword(&newEmployee)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	createdEmployee,err := ec.employeeRepository.CreateEmployee(&newEmployee)
+	if err != nil {
+		if errors.Is(err, repository.ErrUsernameTaken) {
+			ctx.JSON(http.StatusConflict, gin.H{"message" : "Failed to create employee", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to create employee", "details" : err.Error()})
+		return
+	}
+	// Create The response struct 
+	response := EmployeeResponse{
+		Message: "Successfuly Create Employee",
+		Data: *createdEmployee,
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (ec *employeeController) GetAllEmployee(ctx *gin.Context) {
+	employees := []entity.Employee{}
+
+	page, err := parsePageRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid paging", "details" : err.Error()})
+		return
+	}
+
+	filter, err := parseListFilter(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input", "details" : err.Error()})
+		return
+	}
+
+	rows, total, err := ec.employeeRepository.GetEmployee(filter, page)
+	
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidPaging) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid paging", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get all employee data", "details" : err.Error()})
+		return
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		employee := entity.Employee{}
+		err = rows.Scan(&employee.Employee_id,&employee.Name,&employee.Phone_number,&employee.Address,&employee.Username,pq.Array(&employee.Roles),&employee.Deleted_at)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed scanning employee data", "details" : err.Error()})
+			return
+		}
+		employees = append(employees, employee)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error encountred during iteration", "details" : err.Error()})
+		return
+	}
+
+	employees, paging := pageOf(page, total, employees, func(employee entity.Employee) string { return employee.Employee_id })
+
+	response :=EmployeeResponseSlice{
+		Message: "Successfully get all data from employee",
+		Data: employees,
+		Paging: paging,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (ec *employeeController) GetDetailEmployee(ctx *gin.Context) {
+	id,err := strconv.Atoi(ctx.Param("id"))
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Failed convert id. Make sure id is number", "details" : err.Error()})
+		return
+	}
+
+	employee := entity.Employee{}
+
+	detailEmployee, err := ec.employeeRepository.GetDetailEmployee(id,&employee)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get detail employee data", "details" : err.Error()})
+		return
+	}
+
+	// Create The response struct 
+	response := EmployeeResponse{
+		Message: "Successfuly Get Employee Detail",
+		Data: *detailEmployee,
+	}
+	
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (ec *employeeController) UpdateEmployee(ctx *gin.Context) {
+	convertedId, err := strconv.Atoi(ctx.Param("id"))
+	
+	if err != nil {
+	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
+	  return
+	}
+  
+	employee := entity.Employee{}
+  
+	detailEmployee, err := ec.employeeRepository.GetDetailEmployee(convertedId, &employee)
+	if err != nil {
+	  ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to get employee data", "details": err.Error()})
+	  return
+	}
+
+	updateEmployee := entity.Employee{}
+  
+	err = ctx.ShouldBind(&updateEmployee)
+	if err != nil {
+	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid Input", "details": err.Error()})
+	  return
+	}
+  
+	// Update existing employee data
+	if strings.TrimSpace(updateEmployee.Name) != "" {
+	  detailEmployee.Name = updateEmployee.Name
+	}
+	if strings.TrimSpace(updateEmployee.Phone_number) != "" {
+	  detailEmployee.Phone_number = updateEmployee.Phone_number
+	}
+	if strings.TrimSpace(updateEmployee.Address) != "" {
+	  detailEmployee.Address = updateEmployee.Address
+	}
+	if strings.TrimSpace(updateEmployee.Username) != "" {
+	  detailEmployee.Username = updateEmployee.Username
+	}
+	if updateEmployee.Password != "" {
+	  detailEmployee.Password = updateEmployee.Password
+	  err = hashEmployeePassword(detailEmployee)
+	  if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid Input", "details": err.Error()})
+		return
+	  }
+	}
+  
+	updatedEmployee, err := ec.employeeRepository.UpdateEmployee(convertedId,detailEmployee) // Assuming updateEmployee function exists
+	if err != nil {
+	  if errors.Is(err, repository.ErrUsernameTaken) {
+		ctx.JSON(http.StatusConflict, gin.H{"message": "Failed to update employee data", "details": err.Error()})
+		return
+	  }
+	  ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update employee data", "details": err.Error()})
+	  return
+	}
+  
+	// Create The response struct 
+	response := EmployeeResponse{
+	  Message: "Successfully Updated Employee Data",
+	  Data: *updatedEmployee,
+	}
+  
+	ctx.JSON(http.StatusOK, response) // Updated status code to 200 (OK)
+  }
+
+  func (ec *employeeController) DeleteEmployee(ctx *gin.Context) {
+	convertedId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
+	  return
+	}
+
+	employee := entity.Employee{}
+
+	isEmployeeExist,err := ec.employeeRepository.IsEmployeeExist(convertedId,&employee)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Employee", "details" : err.Error()})
+		return
+	}
+	if !isEmployeeExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "employee not found"})
+		return
+	}
+
+	_,err = ec.employeeRepository.DeleteEmployee(convertedId)
+	if err != nil {
+		if errors.Is(err, repository.ErrLastAdmin) {
+			ctx.JSON(http.StatusConflict, gin.H{"message" : "Failed to delete employee", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error Deleting Employee", "details" :err.Error()})
+		return
+	}
+
+	response := struct {
+		Message string `json:"message"`
+		Data string `json:"data"`
+	}{
+		Message: "Successfully deleted data",
+		Data: "OK",
+	}
+
+	ctx.JSON(http.StatusOK,response)
+}
+func (ec *employeeController) RestoreEmployee(ctx *gin.Context) {
+	convertedId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
+		return
+	}
+
+	isRestored,err := ec.employeeRepository.RestoreEmployee(convertedId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error Restoring Employee", "details" : err.Error()})
+		return
+	}
+	if !isRestored {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "employee not found"})
+		return
+	}
+
+	ec.respondEmployeeRoles(ctx, convertedId, "Successfully Restored Employee")
+}
+
+func (ec *employeeController) AssignRole(ctx *gin.Context) {
+	convertedId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
+		return
+	}
+
+	var request struct {
+		Role string `json:"role"`
+	}
+	err = ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	if !entity.IsValidRole(request.Role) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid role", "details": "role must be one of admin, cashier, washer"})
+		return
+	}
+
+	if !ec.employeeExist(ctx, convertedId) {
+		return
+	}
+
+	err = ec.employeeRepository.AssignRole(convertedId, request.Role)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to assign role", "details" : err.Error()})
+		return
+	}
+
+	ec.respondEmployeeRoles(ctx, convertedId, "Successfully Assign Role")
+}
+
+func (ec *employeeController) RevokeRole(ctx *gin.Context) {
+	convertedId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
+		return
+	}
+
+	role := ctx.Param("role")
+	if !entity.IsValidRole(role) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid role", "details": "role must be one of admin, cashier, washer"})
+		return
+	}
+
+	if !ec.employeeExist(ctx, convertedId) {
+		return
+	}
+
+	err = ec.employeeRepository.RevokeRole(convertedId, role)
+	if err != nil {
+		if errors.Is(err, repository.ErrLastAdmin) {
+			ctx.JSON(http.StatusConflict, gin.H{"message" : "Failed to revoke role", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to revoke role", "details" : err.Error()})
+		return
+	}
+
+	ec.respondEmployeeRoles(ctx, convertedId, "Successfully Revoke Role")
+}
+
+func (ec *employeeController) employeeExist(ctx *gin.Context, id int) bool {
+	employee := entity.Employee{}
+
+	isEmployeeExist,err := ec.employeeRepository.IsEmployeeExist(id,&employee)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Employee", "details" : err.Error()})
+		return false
+	}
+	if !isEmployeeExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "employee not found"})
+		return false
+	}
+	return true
+}
+
+// Role changes apply to the employee's next login or token refresh
+func (ec *employeeController) respondEmployeeRoles(ctx *gin.Context, id int, message string) {
+	employee := entity.Employee{}
+
+	detailEmployee, err := ec.employeeRepository.GetDetailEmployee(id,&employee)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get detail employee data", "details" : err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, EmployeeResponse{Message: message, Data: *detailEmployee})
+}
+
+// Replace the plain password of employee with its hash, the plain one never leaves the request
+func hashEmployeePassword(employee *entity.Employee) error {
+	if len(employee.Password) < 8 {
+		return errors.New("password must be at least 8 characters")
+	}
+
+	hash, err := utils.HashPassword(employee.Password)
+	if err != nil {
+		return err
+	}
+	employee.Password_hash = hash
+	employee.Password = ""
+	return nil
+}
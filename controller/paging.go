@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageSize = 10
+	maxPageSize     = 100
+)
+
+// Response envelope of every list endpoint
+type PagedResponse[T any] struct {
+	Message string        `json:"message"`
+	Data    []T           `json:"data"`
+	Paging  entity.Paging `json:"paging"`
+}
+
+// Read page, size, cursor and sort query params. Sending cursor (even empty) switch to cursor paging.
+func parsePageRequest(ctx *gin.Context) (entity.Page_request, error) {
+	var err error
+	page := entity.Page_request{Page: 1, Size: defaultPageSize}
+
+	if ctx.Query("page") != "" {
+		page.Page, err = strconv.Atoi(ctx.Query("page"))
+		if err != nil || page.Page < 1 {
+			return page, fmt.Errorf("page must be a number greater than 0")
+		}
+	}
+
+	if ctx.Query("size") != "" {
+		page.Size, err = strconv.Atoi(ctx.Query("size"))
+		if err != nil || page.Size < 1 || page.Size > maxPageSize {
+			return page, fmt.Errorf("size must be a number between 1 and %d", maxPageSize)
+		}
+	}
+
+	page.Cursor, page.Cursor_mode = ctx.GetQuery("cursor")
+
+	// sort=name,-price sort by name ascending then price descending
+	for _, field := range strings.Split(ctx.Query("sort"), ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if strings.HasPrefix(field, "-") {
+			page.Sort = append(page.Sort, entity.Sort_field{Field: field[1:], Desc: true})
+		} else {
+			page.Sort = append(page.Sort, entity.Sort_field{Field: strings.TrimPrefix(field, "+")})
+		}
+	}
+
+	return page, nil
+}
+
+// Read q and includeDeleted query params of the customer, employee and product lists
+func parseListFilter(ctx *gin.Context) (repository.ListFilter, error) {
+	filter := repository.ListFilter{Q: ctx.Query("q")}
+
+	if ctx.Query("includeDeleted") != "" {
+		includeDeleted, err := strconv.ParseBool(ctx.Query("includeDeleted"))
+		if err != nil {
+			return filter, fmt.Errorf("includeDeleted must be true or false")
+		}
+		filter.IncludeDeleted = includeDeleted
+	}
+
+	return filter, nil
+}
+
+// Drop the extra row fetched in cursor mode and build the paging metadata
+func pageOf[T any](page entity.Page_request, total int, items []T, idOf func(T) string) ([]T, entity.Paging) {
+	paging := entity.Paging{Size: page.Size, Total: total}
+
+	if page.Cursor_mode {
+		if len(items) > page.Size {
+			items = items[:page.Size]
+			paging.Next_cursor = repository.EncodeCursor(idOf(items[len(items)-1]))
+		}
+		return items, paging
+	}
+
+	paging.Page = page.Page
+	paging.Total_pages = (total + page.Size - 1) / page.Size
+	return items, paging
+}
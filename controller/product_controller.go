@@ -1,253 +1,490 @@
-package controller
-
-import (
-	"net/http"
-	"strconv"
-	"strings"
-	"submission-project-enigma-laundry/entity"
-	"submission-project-enigma-laundry/repository"
-	"github.com/gin-gonic/gin"
-)
-
-type ProductController interface {
-	CreateProduct(ctx *gin.Context)
-	ListProduct(ctx *gin.Context)
-	GetDetailProduct(ctx *gin.Context)
-	UpdateProduct(ctx *gin.Context)
-	DeleteProduct(ctx *gin.Context)
-}
-
-type productController struct {
-	productRepository repository.ProductRepository
-}
-
-type ProductResponse struct {
-	Message string `json:"message"`
-	Data entity.Product `json:"data"`
-}
-
-type ProductResponseSlice struct {
-	Message string `json:"message"`
-	Data []entity.Product `json:"data"`
-}
-
-func NewProductController(repo repository.ProductRepository) ProductController {
-	return &productController{productRepository: repo}
-}
-
-func (pc *productController) CreateProduct(ctx *gin.Context) {
-	var newProduct entity.Product
-	err := ctx.ShouldBind(&newProduct)
-
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
-		return
-	}
-
-	createdProduct,err := pc.productRepository.CreateProduct(&newProduct)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to create product", "details" : err.Error()})
-		return
-	}
-	// Create The response struct 
-	response := ProductResponse{
-		Message: "Successfuly Create Product",
-		Data: *createdProduct,
-	}
-
-	ctx.JSON(http.StatusCreated, response)
-}
-
-func (pc *productController) ListProduct(ctx *gin.Context) {
-	products := []entity.Product{}
-	productName := ctx.Query("productName")
-	if strings.TrimSpace(productName) != "" {
-		rows, err := pc.productRepository.GetProductByName(productName)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get all product by name", "details" : err.Error()})
-			return
-		}
-
-		defer rows.Close()
-
-		for rows.Next() {
-			product := entity.Product{}
-			err = rows.Scan(&product.Product_id,&product.Product_name,&product.Price,&product.Unit)
-			if err != nil {
-				ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed scanning product data by name", "details" : err.Error()})
-				return
-			}
-			products = append(products, product)
-		}
-
-		err = rows.Err()
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error encountred during iteration", "details" : err.Error()})
-			return
-		}
-
-		response :=ProductResponseSlice{
-			Message: "Successfully get data by name from product",
-			Data: products,
-		}
-
-		ctx.JSON(http.StatusOK, response)
-		return
-	} else {
-		rows, err := pc.productRepository.GetProduct()
-	
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get all product data", "details" : err.Error()})
-			return
-		}
-
-		defer rows.Close()
-
-		for rows.Next() {
-			product := entity.Product{}
-			err = rows.Scan(&product.Product_id,&product.Product_name,&product.Unit,&product.Price)
-			if err != nil {
-				ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed scanning product data", "details" : err.Error()})
-				return
-			}
-			products = append(products, product)
-		}
-
-		err = rows.Err()
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error encountred during iteration", "details" : err.Error()})
-			return
-		}
-
-		response :=ProductResponseSlice{
-			Message: "Successfully get all data from product",
-			Data: products,
-		}
-
-		ctx.JSON(http.StatusOK, response)
-		return
-	}
-}
-
-func (pc *productController) GetDetailProduct(ctx *gin.Context) {
-	id,err := strconv.Atoi(ctx.Param("id"))
-
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Failed convert id. Make sure id is number", "details" : err.Error()})
-		return
-	}
-
-	product := entity.Product{}
-
-	detailProduct, err := pc.productRepository.GetDetailProduct(id,&product)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get detail product data", "details" : err.Error()})
-		return
-	}
-
-	// Create The response struct 
-	response := ProductResponse{
-		Message: "Successfuly Get product Detail",
-		Data: *detailProduct,
-	}
-	
-	ctx.JSON(http.StatusOK, response)
-}
-
-func (pc *productController) UpdateProduct(ctx *gin.Context) {
-	convertedId, err := strconv.Atoi(ctx.Param("id"))
-	
-	if err != nil {
-	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
-	  return
-	}
-  
-	product := entity.Product{}
-  
-	detailProduct, err := pc.productRepository.GetDetailProduct(convertedId, &product)
-	if err != nil {
-	  ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to get product data", "details": err.Error()})
-	  return
-	}
-
-	updateProduct := entity.Product{}
-  
-	err = ctx.ShouldBind(&updateProduct)
-	if err != nil {
-	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid Input", "details": err.Error()})
-	  return
-	}
-  
-	// Update existing product data
-	if strings.TrimSpace(updateProduct.Product_name) != "" {
-	  detailProduct.Product_name = updateProduct.Product_name
-	}
-	if updateProduct.Price != 0 {
-	  detailProduct.Price = updateProduct.Price
-	}
-	if strings.TrimSpace(updateProduct.Unit) != "" {
-	  detailProduct.Unit = updateProduct.Unit
-	}
-  
-	updatedProduct, err := pc.productRepository.UpdateProduct(convertedId,detailProduct) // Assuming updateProduct function exists
-	if err != nil {
-	  ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update product data", "details": err.Error()})
-	  return
-	}
-  
-	// Create The response struct 
-	response := ProductResponse{
-	  Message: "Successfully Updated Product Data",
-	  Data: *updatedProduct,
-	}
-  
-	ctx.JSON(http.StatusOK, response) // Updated status code to 200 (OK)
-  }
-
-  func (pc *productController) DeleteProduct(ctx *gin.Context) {
-	convertedId, err := strconv.Atoi(ctx.Param("id"))
-	if err != nil {
-	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
-	  return
-	}
-
-	product := entity.Product{}
-
-	isProductExist,err := pc.productRepository.IsProductExist(convertedId,&product)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Product", "details" : err.Error()})
-		return
-	}
-	if !isProductExist {
-		ctx.JSON(http.StatusNotFound, gin.H{"message" : "product not found"})
-		return
-	}
-
-	transaction_detail := entity.Transaction_detail{}
-
-	isProductInTransaction,err := pc.productRepository.ProductInTransactionDetail(convertedId,&transaction_detail)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError,gin.H{"message" : "Error While Checking Product in Transaction", "details" : err.Error()})
-		return
-	} 
-	if isProductInTransaction {
-		ctx.JSON(http.StatusConflict,gin.H{"message" : "Product is being used in transaction. Please delete the transaction first"})
-		return
-	}
-
-	_,err = pc.productRepository.DeleteProduct(convertedId)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error Deleting Product", "details" :err.Error()})
-		return
-	}
-
-	response := struct {
-		Message string `json:"message"`
-		Data string `json:"data"`
-	}{
-		Message: "Successfully deleted data",
-		Data: "OK",
-	}
-
-	ctx.JSON(http.StatusOK,response)
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/repository"
+	"time"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+type ProductController interface {
+	CreateProduct(ctx *gin.Context)
+	ListProduct(ctx *gin.Context)
+	GetDetailProduct(ctx *gin.Context)
+	UpdateProduct(ctx *gin.Context)
+	DeleteProduct(ctx *gin.Context)
+	RestoreProduct(ctx *gin.Context)
+	GetProductPrices(ctx *gin.Context)
+}
+
+type productController struct {
+	productRepository repository.ProductRepository
+	categoryRepository repository.CategoryRepository
+}
+
+type ProductResponse struct {
+	Message string `json:"message"`
+	Data entity.Product `json:"data"`
+}
+
+type ProductResponseSlice = PagedResponse[entity.Product]
+
+type ProductPricesResponse struct {
+	Message string `json:"message"`
+	Data []entity.Product_price `json:"data"`
+}
+
+func NewProductController(repo repository.ProductRepository, categoryRepo repository.CategoryRepository) ProductController {
+	return &productController{productRepository: repo, categoryRepository: categoryRepo}
+}
+
+func (pc *productController) CreateProduct(ctx *gin.Context) {
+	// A new product is on sale unless active is false
+	newProduct := entity.Product{Active: true}
+	err := ctx.ShouldBind(&newProduct)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	if newProduct.Pricing_model == "" {
+		newProduct.Pricing_model = entity.Pricing_per_piece
+	}
+	if newProduct.Product_type == "" {
+		newProduct.Product_type = entity.Product_type_service
+	}
+	err = validateProductType(&newProduct)
+	if err == nil {
+		err = validatePricing(&newProduct)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	if !pc.coveredProductsValid(ctx, &newProduct) || !pc.categoryValid(ctx, &newProduct) {
+		return
+	}
+
+	createdProduct,err := pc.productRepository.CreateProduct(&newProduct)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to create product", "details" : err.Error()})
+		return
+	}
+	// Create The response struct 
+	response := ProductResponse{
+		Message: "Successfuly Create Product",
+		Data: *createdProduct,
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (pc *productController) ListProduct(ctx *gin.Context) {
+	products := []entity.Product{}
+
+	page, err := parsePageRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid paging", "details" : err.Error()})
+		return
+	}
+
+	filter, err := parseListFilter(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input", "details" : err.Error()})
+		return
+	}
+
+	// productName is the older name of q
+	if strings.TrimSpace(filter.Q) == "" {
+		filter.Q = ctx.Query("productName")
+	}
+	if ctx.Query("categoryId") != "" {
+		filter.CategoryId, err = strconv.Atoi(ctx.Query("categoryId"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input", "details" : "categoryId must be a number"})
+			return
+		}
+	}
+
+	rows, total, err := pc.productRepository.GetProduct(filter, page)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidPaging) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid paging", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get all product data", "details" : err.Error()})
+		return
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		product := entity.Product{}
+		err = rows.Scan(&product.Product_id,&product.Product_name,&product.Unit,&product.Price,&product.Pricing_model,&product.Min_weight,&product.Tax_inclusive,&product.Product_type,&product.Quota,pq.Array(&product.Covered_product_ids),&product.Category_id,&product.Description,&product.Sort_order,&product.Active,&product.Deleted_at)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed scanning product data", "details" : err.Error()})
+			return
+		}
+		products = append(products, product)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error encountred during iteration", "details" : err.Error()})
+		return
+	}
+
+	products, paging := pageOf(page, total, products, func(product entity.Product) string { return product.Product_id })
+
+	response :=ProductResponseSlice{
+		Message: "Successfully get all data from product",
+		Data: products,
+		Paging: paging,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (pc *productController) GetDetailProduct(ctx *gin.Context) {
+	id,err := strconv.Atoi(ctx.Param("id"))
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Failed convert id. Make sure id is number", "details" : err.Error()})
+		return
+	}
+
+	product := entity.Product{}
+
+	detailProduct, err := pc.productRepository.GetDetailProduct(id,&product)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get detail product data", "details" : err.Error()})
+		return
+	}
+
+	// Create The response struct 
+	response := ProductResponse{
+		Message: "Successfuly Get product Detail",
+		Data: *detailProduct,
+	}
+	
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (pc *productController) UpdateProduct(ctx *gin.Context) {
+	convertedId, err := strconv.Atoi(ctx.Param("id"))
+	
+	if err != nil {
+	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
+	  return
+	}
+  
+	product := entity.Product{}
+  
+	detailProduct, err := pc.productRepository.GetDetailProduct(convertedId, &product)
+	if err != nil {
+	  ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to get product data", "details": err.Error()})
+	  return
+	}
+
+	// Pointer fields left out keep the current value, an empty categoryId removes the category.
+	// Without priceEffectiveFrom a new price is charged from today
+	updateProduct := struct {
+		entity.Product
+		Min_weight *float64 `json:"minWeight"`
+		Tax_inclusive *bool `json:"taxInclusive"`
+		Price_effective_from string `json:"priceEffectiveFrom"`
+		Category_id *string `json:"categoryId"`
+		Description *string `json:"description"`
+		Sort_order *int `json:"sortOrder"`
+		Active *bool `json:"active"`
+	}{}
+  
+	err = ctx.ShouldBind(&updateProduct)
+	if err != nil {
+	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid Input", "details": err.Error()})
+	  return
+	}
+  
+	// Update existing product data
+	if strings.TrimSpace(updateProduct.Product_name) != "" {
+	  detailProduct.Product_name = updateProduct.Product_name
+	}
+	if updateProduct.Price != 0 {
+	  detailProduct.Price = updateProduct.Price
+	}
+	detailProduct.Price_effective_from = startOfDay(time.Now())
+	if updateProduct.Price_effective_from != "" {
+	  if updateProduct.Price == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid Input", "details": "priceEffectiveFrom needs a price"})
+		return
+	  }
+	  effectiveFrom, err := parseDate(updateProduct.Price_effective_from)
+	  if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid Input", "details": err.Error()})
+		return
+	  }
+	  if effectiveFrom.Before(detailProduct.Price_effective_from) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid Input", "details": "priceEffectiveFrom can not be before today"})
+		return
+	  }
+	  detailProduct.Price_effective_from = startOfDay(effectiveFrom)
+	}
+	if strings.TrimSpace(updateProduct.Unit) != "" {
+	  detailProduct.Unit = updateProduct.Unit
+	}
+	if updateProduct.Pricing_model != "" {
+	  detailProduct.Pricing_model = updateProduct.Pricing_model
+	}
+	if updateProduct.Min_weight != nil {
+	  detailProduct.Min_weight = *updateProduct.Min_weight
+	}
+	if updateProduct.Tax_inclusive != nil {
+	  detailProduct.Tax_inclusive = *updateProduct.Tax_inclusive
+	}
+	if updateProduct.Product_type != "" {
+	  detailProduct.Product_type = updateProduct.Product_type
+	}
+	if updateProduct.Quota != 0 {
+	  detailProduct.Quota = updateProduct.Quota
+	}
+	if updateProduct.Covered_product_ids != nil {
+	  detailProduct.Covered_product_ids = updateProduct.Covered_product_ids
+	}
+	if updateProduct.Category_id != nil {
+	  detailProduct.Category_id = strings.TrimSpace(*updateProduct.Category_id)
+	}
+	if updateProduct.Description != nil {
+	  detailProduct.Description = *updateProduct.Description
+	}
+	if updateProduct.Sort_order != nil {
+	  detailProduct.Sort_order = *updateProduct.Sort_order
+	}
+	if updateProduct.Active != nil {
+	  detailProduct.Active = *updateProduct.Active
+	}
+
+	err = validateProductType(detailProduct)
+	if err == nil {
+	  err = validatePricing(detailProduct)
+	}
+	if err != nil {
+	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid Input", "details": err.Error()})
+	  return
+	}
+
+	if !pc.coveredProductsValid(ctx, detailProduct) || !pc.categoryValid(ctx, detailProduct) {
+	  return
+	}
+  
+	updatedProduct, err := pc.productRepository.UpdateProduct(convertedId,detailProduct) // Assuming updateProduct function exists
+	if err != nil {
+	  ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update product data", "details": err.Error()})
+	  return
+	}
+  
+	// Create The response struct 
+	response := ProductResponse{
+	  Message: "Successfully Updated Product Data",
+	  Data: *updatedProduct,
+	}
+  
+	ctx.JSON(http.StatusOK, response) // Updated status code to 200 (OK)
+  }
+
+  func (pc *productController) DeleteProduct(ctx *gin.Context) {
+	convertedId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+	  ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
+	  return
+	}
+
+	product := entity.Product{}
+
+	isProductExist,err := pc.productRepository.IsProductExist(convertedId,&product)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Product", "details" : err.Error()})
+		return
+	}
+	if !isProductExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "product not found"})
+		return
+	}
+
+	_,err = pc.productRepository.DeleteProduct(convertedId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error Deleting Product", "details" :err.Error()})
+		return
+	}
+
+	response := struct {
+		Message string `json:"message"`
+		Data string `json:"data"`
+	}{
+		Message: "Successfully deleted data",
+		Data: "OK",
+	}
+
+	ctx.JSON(http.StatusOK,response)
+}
+
+func (pc *productController) RestoreProduct(ctx *gin.Context) {
+	convertedId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
+		return
+	}
+
+	isRestored,err := pc.productRepository.RestoreProduct(convertedId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error Restoring Product", "details" : err.Error()})
+		return
+	}
+	if !isRestored {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "product not found"})
+		return
+	}
+
+	product := entity.Product{}
+
+	restoredProduct, err := pc.productRepository.GetDetailProduct(convertedId, &product)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get detail product data", "details" : err.Error()})
+		return
+	}
+
+	response := ProductResponse{
+		Message: "Successfully Restored Product",
+		Data: *restoredProduct,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Price history of the product including the prices not effective yet
+func (pc *productController) GetProductPrices(ctx *gin.Context) {
+	convertedId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
+		return
+	}
+
+	product := entity.Product{}
+
+	_, err = pc.productRepository.GetDetailProduct(convertedId, &product)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get detail product data", "details" : err.Error()})
+		return
+	}
+
+	prices, err := pc.productRepository.GetProductPrices(convertedId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get product prices", "details" : err.Error()})
+		return
+	}
+
+	response := ProductPricesResponse{
+		Message: "Successfully Get Product Prices",
+		Data: prices,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Minimum weight only applies to per kg products
+func validatePricing(product *entity.Product) error {
+	if !entity.IsValidPricingModel(product.Pricing_model) {
+		return errors.New("pricingModel must be one of per_kg, per_piece, flat")
+	}
+	if product.Min_weight < 0 {
+		return errors.New("minWeight can not be negative")
+	}
+	if product.Pricing_model != entity.Pricing_per_kg {
+		product.Min_weight = 0
+	}
+	return nil
+}
+
+// A package is sold once per subscription, so it is flat priced and needs a kg quota
+func validateProductType(product *entity.Product) error {
+	if !entity.IsValidProductType(product.Product_type) {
+		return errors.New("productType must be one of service, package")
+	}
+	if product.Product_type == entity.Product_type_service {
+		product.Quota = 0
+		product.Covered_product_ids = nil
+		return nil
+	}
+	if product.Quota <= 0 {
+		return errors.New("quota of a package must be greater than 0")
+	}
+	if len(product.Covered_product_ids) == 0 {
+		return errors.New("coveredProductIds of a package can not be empty")
+	}
+	product.Pricing_model = entity.Pricing_flat
+	product.Min_weight = 0
+	return nil
+}
+
+// Quota is in kg, so a package can only cover per kg services
+func (pc *productController) coveredProductsValid(ctx *gin.Context, product *entity.Product) bool {
+	for _, productId := range product.Covered_product_ids {
+		converIdProduct, err := strconv.Atoi(productId)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert product id. Make sure product id is number", "details": err.Error()})
+			return false
+		}
+
+		covered := entity.Product{}
+		isProductExist, err := pc.productRepository.IsProductExist(converIdProduct, &covered)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Product", "details" : err.Error()})
+			return false
+		}
+		if !isProductExist {
+			ctx.JSON(http.StatusNotFound, gin.H{"message" : "product not found", "details" : "product " + productId + " does not exist"})
+			return false
+		}
+
+		_, err = pc.productRepository.GetDetailProduct(converIdProduct, &covered)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get product data", "details" : err.Error()})
+			return false
+		}
+		if covered.Product_type != entity.Product_type_service || covered.Pricing_model != entity.Pricing_per_kg {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input", "details" : "product " + productId + " is not a per_kg service"})
+			return false
+		}
+	}
+	return true
+}
+
+// An inactive category can still be picked, its products are only hidden from the catalogue
+func (pc *productController) categoryValid(ctx *gin.Context, product *entity.Product) bool {
+	if product.Category_id == "" {
+		return true
+	}
+
+	categoryId, err := strconv.Atoi(product.Category_id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert category id. Make sure category id is number", "details": err.Error()})
+		return false
+	}
+
+	_, err = pc.categoryRepository.GetCategory(categoryId)
+	if err != nil {
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message" : "category not found", "details" : "category " + product.Category_id + " does not exist"})
+			return false
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Category", "details" : err.Error()})
+		return false
+	}
+	return true
 }
\ No newline at end of file
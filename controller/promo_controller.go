@@ -0,0 +1,264 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/repository"
+	"time"
+	"github.com/gin-gonic/gin"
+)
+
+type PromoController interface {
+	ListPromo(ctx *gin.Context)
+	GetPromo(ctx *gin.Context)
+	CreatePromo(ctx *gin.Context)
+	UpdatePromo(ctx *gin.Context)
+}
+
+type promoController struct {
+	promoRepository   repository.PromoRepository
+	productRepository repository.ProductRepository
+}
+
+// Fields left out keep their value on update, an empty productIds removes the product restriction
+type PromoRequest struct {
+	Code          string   `json:"code"`
+	Name          string   `json:"name"`
+	DiscountType  string   `json:"discountType"`
+	DiscountValue *int     `json:"discountValue"`
+	MaxDiscount   *int     `json:"maxDiscount"`
+	MinSpend      *int     `json:"minSpend"`
+	StartsAt      string   `json:"startsAt"`
+	EndsAt        string   `json:"endsAt"`
+	UsageLimit    *int     `json:"usageLimit"`
+	ProductIds    []string `json:"productIds"`
+}
+
+type PromoResponse struct {
+	Message string `json:"message"`
+	Data entity.Promo `json:"data"`
+}
+
+type PromoResponseSlice struct {
+	Message string `json:"message"`
+	Data []entity.Promo `json:"data"`
+}
+
+func NewPromoController(promoRepo repository.PromoRepository, productRepo repository.ProductRepository) PromoController {
+	return &promoController{promoRepository: promoRepo, productRepository: productRepo}
+}
+
+func (pc *promoController) ListPromo(ctx *gin.Context) {
+	promos, err := pc.promoRepository.GetPromos()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get promos", "details" : err.Error()})
+		return
+	}
+
+	response := PromoResponseSlice{
+		Message: "Successfully get all promos",
+		Data: promos,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (pc *promoController) GetPromo(ctx *gin.Context) {
+	promo, err := pc.promoRepository.GetPromo(promoCode(ctx.Param("code")))
+	if err != nil {
+		if errors.Is(err, repository.ErrPromoNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message" : "promo not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get promo", "details" : err.Error()})
+		return
+	}
+
+	response := PromoResponse{
+		Message: "Successfully Get Promo",
+		Data: *promo,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (pc *promoController) CreatePromo(ctx *gin.Context) {
+	var request PromoRequest
+	err := ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	newPromo := entity.Promo{Code: promoCode(request.Code)}
+	if newPromo.Code == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : "code is required"})
+		return
+	}
+	if request.StartsAt == "" || request.EndsAt == "" || request.DiscountValue == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : "discountValue, startsAt and endsAt are required"})
+		return
+	}
+
+	err = applyPromoRequest(&newPromo, request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	if !pc.promoProductsExist(ctx, newPromo.Product_ids) {
+		return
+	}
+
+	createdPromo, err := pc.promoRepository.CreatePromo(&newPromo)
+	if err != nil {
+		if errors.Is(err, repository.ErrPromoCodeTaken) {
+			ctx.JSON(http.StatusConflict, gin.H{"message" : "Promo already exists", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to create promo", "details" : err.Error()})
+		return
+	}
+
+	response := PromoResponse{
+		Message: "Successfully Create Promo",
+		Data: *createdPromo,
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (pc *promoController) UpdatePromo(ctx *gin.Context) {
+	promo, err := pc.promoRepository.GetPromo(promoCode(ctx.Param("code")))
+	if err != nil {
+		if errors.Is(err, repository.ErrPromoNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message" : "promo not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get promo", "details" : err.Error()})
+		return
+	}
+
+	var request PromoRequest
+	err = ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	err = applyPromoRequest(promo, request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	if !pc.promoProductsExist(ctx, promo.Product_ids) {
+		return
+	}
+
+	updatedPromo, err := pc.promoRepository.UpdatePromo(promo.Code, promo)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to update promo", "details" : err.Error()})
+		return
+	}
+
+	response := PromoResponse{
+		Message: "Successfully Updated Promo",
+		Data: *updatedPromo,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (pc *promoController) promoProductsExist(ctx *gin.Context, productIds []string) bool {
+	for _, productId := range productIds {
+		converIdProduct, err := strconv.Atoi(productId)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert product id. Make sure product id is number", "details": err.Error()})
+			return false
+		}
+
+		product := entity.Product{}
+		isProductExist, err := pc.productRepository.IsProductExist(converIdProduct, &product)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Product", "details" : err.Error()})
+			return false
+		}
+		if !isProductExist {
+			ctx.JSON(http.StatusNotFound, gin.H{"message" : "product not found", "details" : "product " + productId + " does not exist"})
+			return false
+		}
+	}
+	return true
+}
+
+// Promo codes are case insensitive, ex : lebaran10 is LEBARAN10
+func promoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Copy the given request fields into the promo and check the result
+func applyPromoRequest(promo *entity.Promo, request PromoRequest) error {
+	var err error
+
+	if strings.TrimSpace(request.Name) != "" {
+		promo.Name = request.Name
+	}
+	if request.DiscountType != "" {
+		promo.Discount_type = request.DiscountType
+	}
+	if request.DiscountValue != nil {
+		promo.Discount_value = *request.DiscountValue
+	}
+	if request.MaxDiscount != nil {
+		promo.Max_discount = *request.MaxDiscount
+	}
+	if request.MinSpend != nil {
+		promo.Min_spend = *request.MinSpend
+	}
+	if request.UsageLimit != nil {
+		promo.Usage_limit = *request.UsageLimit
+	}
+	if request.ProductIds != nil {
+		promo.Product_ids = request.ProductIds
+	}
+	if request.StartsAt != "" {
+		promo.Starts_at, err = parseDate(request.StartsAt)
+		if err != nil {
+			return err
+		}
+	}
+	if request.EndsAt != "" {
+		promo.Ends_at, err = parseDate(request.EndsAt)
+		if err != nil {
+			return err
+		}
+		// A date without time lasts the whole day
+		if promo.Ends_at.Equal(startOfDay(promo.Ends_at)) {
+			promo.Ends_at = promo.Ends_at.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+	}
+
+	if strings.TrimSpace(promo.Name) == "" {
+		return errors.New("name is required")
+	}
+	if !entity.IsValidDiscountType(promo.Discount_type) {
+		return errors.New("discountType must be one of percent, fixed")
+	}
+	if promo.Discount_value <= 0 {
+		return errors.New("discountValue must be greater than 0")
+	}
+	if promo.Discount_type == entity.Discount_percent && promo.Discount_value > 100 {
+		return errors.New("percent discountValue can not be more than 100")
+	}
+	if promo.Max_discount < 0 || promo.Min_spend < 0 || promo.Usage_limit < 0 {
+		return errors.New("maxDiscount, minSpend and usageLimit can not be negative")
+	}
+	if promo.Ends_at.Before(promo.Starts_at) {
+		return errors.New("endsAt can not be before startsAt")
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type ServiceTierController interface {
+	ListServiceTier(ctx *gin.Context)
+	CreateServiceTier(ctx *gin.Context)
+	UpdateServiceTier(ctx *gin.Context)
+}
+
+type serviceTierController struct {
+	serviceTierRepository repository.ServiceTierRepository
+}
+
+type ServiceTierResponse struct {
+	Message string `json:"message"`
+	Data entity.Service_tier `json:"data"`
+}
+
+type ServiceTierResponseSlice struct {
+	Message string `json:"message"`
+	Data []entity.Service_tier `json:"data"`
+}
+
+func NewServiceTierController(repo repository.ServiceTierRepository) ServiceTierController {
+	return &serviceTierController{serviceTierRepository: repo}
+}
+
+func (sc *serviceTierController) ListServiceTier(ctx *gin.Context) {
+	tiers, err := sc.serviceTierRepository.GetServiceTiers()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get service tiers", "details" : err.Error()})
+		return
+	}
+
+	response := ServiceTierResponseSlice{
+		Message: "Successfully get all service tiers",
+		Data: tiers,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (sc *serviceTierController) CreateServiceTier(ctx *gin.Context) {
+	var newTier entity.Service_tier
+	err := ctx.ShouldBind(&newTier)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	newTier.Code = strings.TrimSpace(newTier.Code)
+	if newTier.Code == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : "code is required"})
+		return
+	}
+
+	err = validateServiceTier(&newTier)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	createdTier, err := sc.serviceTierRepository.CreateServiceTier(&newTier)
+	if err != nil {
+		if errors.Is(err, repository.ErrServiceTierTaken) {
+			ctx.JSON(http.StatusConflict, gin.H{"message" : "Service tier already exists", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to create service tier", "details" : err.Error()})
+		return
+	}
+
+	response := ServiceTierResponse{
+		Message: "Successfully Create Service Tier",
+		Data: *createdTier,
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (sc *serviceTierController) UpdateServiceTier(ctx *gin.Context) {
+	tier, err := sc.serviceTierRepository.GetServiceTier(ctx.Param("code"))
+	if err != nil {
+		if errors.Is(err, repository.ErrServiceTierNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message" : "service tier not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get service tier", "details" : err.Error()})
+		return
+	}
+
+	var request struct {
+		Name             string `json:"name"`
+		Surcharge_type   string `json:"surchargeType"`
+		Surcharge_value  *int   `json:"surchargeValue"`
+		Turnaround_hours *int   `json:"turnaroundHours"`
+	}
+	err = ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	if strings.TrimSpace(request.Name) != "" {
+		tier.Name = request.Name
+	}
+	if request.Surcharge_type != "" {
+		tier.Surcharge_type = request.Surcharge_type
+	}
+	if request.Surcharge_value != nil {
+		tier.Surcharge_value = *request.Surcharge_value
+	}
+	if request.Turnaround_hours != nil {
+		tier.Turnaround_hours = *request.Turnaround_hours
+	}
+
+	err = validateServiceTier(tier)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	updatedTier, err := sc.serviceTierRepository.UpdateServiceTier(ctx.Param("code"), tier)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to update service tier", "details" : err.Error()})
+		return
+	}
+
+	response := ServiceTierResponse{
+		Message: "Successfully Updated Service Tier",
+		Data: *updatedTier,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func validateServiceTier(tier *entity.Service_tier) error {
+	if strings.TrimSpace(tier.Name) == "" {
+		return errors.New("name is required")
+	}
+	if !entity.IsValidSurchargeType(tier.Surcharge_type) {
+		return errors.New("surchargeType must be one of percent, fixed")
+	}
+	if tier.Surcharge_value < 0 {
+		return errors.New("surchargeValue can not be negative")
+	}
+	if tier.Turnaround_hours <= 0 {
+		return errors.New("turnaroundHours must be greater than 0")
+	}
+	return nil
+}
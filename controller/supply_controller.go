@@ -0,0 +1,396 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/middleware"
+	"submission-project-enigma-laundry/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type SupplyController interface {
+	ListSupply(ctx *gin.Context)
+	GetLowStockReport(ctx *gin.Context)
+	GetSupply(ctx *gin.Context)
+	CreateSupply(ctx *gin.Context)
+	UpdateSupply(ctx *gin.Context)
+	AdjustStock(ctx *gin.Context)
+	GetProductSupplies(ctx *gin.Context)
+	SetProductSupplies(ctx *gin.Context)
+}
+
+type supplyController struct {
+	supplyRepository repository.SupplyRepository
+	productRepository repository.ProductRepository
+}
+
+type SupplyResponse struct {
+	Message string `json:"message"`
+	Data entity.Supply `json:"data"`
+}
+
+type SupplyResponseSlice struct {
+	Message string `json:"message"`
+	Data []entity.Supply `json:"data"`
+}
+
+// Supply with its stock ledger, the latest movement first
+type SupplyDetailResponse struct {
+	Message string `json:"message"`
+	Data struct {
+		entity.Supply
+		Movements []entity.Stock_movement `json:"movements"`
+	} `json:"data"`
+}
+
+// Restock adds to the stock, an adjustment corrects it after a stock count or waste
+type StockAdjustmentRequest struct {
+	Type   string  `json:"type"`
+	Change float64 `json:"change"`
+	Note   string  `json:"note"`
+}
+
+type StockMovementResponse struct {
+	Message string `json:"message"`
+	Data entity.Stock_movement `json:"data"`
+}
+
+type ProductSuppliesRequest struct {
+	Supplies []entity.Product_supply `json:"supplies"`
+}
+
+type ProductSuppliesResponse struct {
+	Message string `json:"message"`
+	Data []entity.Product_supply `json:"data"`
+}
+
+func NewSupplyController(repo repository.SupplyRepository, productRepo repository.ProductRepository) SupplyController {
+	return &supplyController{supplyRepository: repo, productRepository: productRepo}
+}
+
+func (sc *supplyController) ListSupply(ctx *gin.Context) {
+	supplies, err := sc.supplyRepository.GetSupplies(false)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get supplies", "details" : err.Error()})
+		return
+	}
+
+	response := SupplyResponseSlice{
+		Message: "Successfully get all supplies",
+		Data: supplies,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Supplies to reorder, at or below their reorder level
+func (sc *supplyController) GetLowStockReport(ctx *gin.Context) {
+	supplies, err := sc.supplyRepository.GetSupplies(true)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get low stock report", "details" : err.Error()})
+		return
+	}
+
+	response := SupplyResponseSlice{
+		Message: "Successfully get low stock report",
+		Data: supplies,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (sc *supplyController) GetSupply(ctx *gin.Context) {
+	convertedId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
+		return
+	}
+
+	supply, ok := sc.supplyOf(ctx, convertedId)
+	if !ok {
+		return
+	}
+
+	movements, err := sc.supplyRepository.GetStockMovements(convertedId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get stock movements", "details" : err.Error()})
+		return
+	}
+
+	response := SupplyDetailResponse{Message: "Successfully Get Supply Detail"}
+	response.Data.Supply = *supply
+	response.Data.Movements = movements
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (sc *supplyController) CreateSupply(ctx *gin.Context) {
+	var newSupply entity.Supply
+	err := ctx.ShouldBind(&newSupply)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	err = validateSupply(&newSupply)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	createdSupply, err := sc.supplyRepository.CreateSupply(&newSupply)
+	if err != nil {
+		if errors.Is(err, repository.ErrSupplyNameTaken) {
+			ctx.JSON(http.StatusConflict, gin.H{"message" : "Supply already exists", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to create supply", "details" : err.Error()})
+		return
+	}
+
+	response := SupplyResponse{
+		Message: "Successfully Create Supply",
+		Data: *createdSupply,
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (sc *supplyController) UpdateSupply(ctx *gin.Context) {
+	convertedId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
+		return
+	}
+
+	supply, ok := sc.supplyOf(ctx, convertedId)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Name          string   `json:"name"`
+		Unit          string   `json:"unit"`
+		Reorder_level *float64 `json:"reorderLevel"`
+	}
+	err = ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	if strings.TrimSpace(request.Name) != "" {
+		supply.Name = request.Name
+	}
+	if strings.TrimSpace(request.Unit) != "" {
+		supply.Unit = request.Unit
+	}
+	if request.Reorder_level != nil {
+		supply.Reorder_level = *request.Reorder_level
+	}
+
+	err = validateSupply(supply)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	updatedSupply, err := sc.supplyRepository.UpdateSupply(convertedId, supply)
+	if err != nil {
+		if errors.Is(err, repository.ErrSupplyNameTaken) {
+			ctx.JSON(http.StatusConflict, gin.H{"message" : "Supply already exists", "details" : err.Error()})
+			return
+		}
+		if errors.Is(err, repository.ErrSupplyNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message" : "supply not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to update supply", "details" : err.Error()})
+		return
+	}
+
+	response := SupplyResponse{
+		Message: "Successfully Updated Supply",
+		Data: *updatedSupply,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (sc *supplyController) AdjustStock(ctx *gin.Context) {
+	convertedId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
+		return
+	}
+
+	var request StockAdjustmentRequest
+	err = ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	if !entity.IsValidStockAdjustment(request.Type) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid stock adjustment", "details": "type must be one of restock, adjustment"})
+		return
+	}
+	if request.Change == 0 || (request.Type == entity.Stock_restock && request.Change < 0) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid stock adjustment", "details": "change of a restock must be greater than 0, of an adjustment not 0"})
+		return
+	}
+
+	// Adjustment is made by the logged in employee
+	movement := entity.Stock_movement{
+		Supply_id:     strconv.Itoa(convertedId),
+		Employee_id:   strconv.Itoa(ctx.GetInt(middleware.EmployeeIdKey)),
+		Change:        request.Change,
+		Movement_type: request.Type,
+		Note:          strings.TrimSpace(request.Note),
+	}
+	createdMovement, err := sc.supplyRepository.AdjustStock(&movement)
+	if err != nil {
+		if errors.Is(err, repository.ErrSupplyNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message" : "supply not found"})
+			return
+		}
+		if errors.Is(err, repository.ErrStockBelowZero) {
+			ctx.JSON(http.StatusConflict, gin.H{"message" : "Invalid stock adjustment", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to adjust stock", "details" : err.Error()})
+		return
+	}
+
+	response := StockMovementResponse{
+		Message: "Successfully Adjust Stock",
+		Data: *createdMovement,
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// Supplies used by one unit of the product
+func (sc *supplyController) GetProductSupplies(ctx *gin.Context) {
+	convertedId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
+		return
+	}
+
+	product := entity.Product{}
+
+	_, err = sc.productRepository.GetDetailProduct(convertedId, &product)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get detail product data", "details" : err.Error()})
+		return
+	}
+
+	supplies, err := sc.supplyRepository.GetProductSupplies(convertedId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get product supplies", "details" : err.Error()})
+		return
+	}
+
+	response := ProductSuppliesResponse{
+		Message: "Successfully Get Product Supplies",
+		Data: supplies,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Replace the supplies used by the product, an empty list removes them
+func (sc *supplyController) SetProductSupplies(ctx *gin.Context) {
+	convertedId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert id. Make sure id is number", "details": err.Error()})
+		return
+	}
+
+	var request ProductSuppliesRequest
+	err = ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	supplyIds := map[string]bool{}
+	for _, supply := range request.Supplies {
+		_, err = strconv.Atoi(supply.Supply_id)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert supply id. Make sure supply id is number", "details": err.Error()})
+			return
+		}
+		if supply.Usage_per_unit <= 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input", "details" : "usagePerUnit must be greater than 0"})
+			return
+		}
+		if supplyIds[supply.Supply_id] {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input", "details" : "supply " + supply.Supply_id + " is listed twice"})
+			return
+		}
+		supplyIds[supply.Supply_id] = true
+	}
+
+	product := entity.Product{}
+
+	isProductExist, err := sc.productRepository.IsProductExist(convertedId, &product)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Product", "details" : err.Error()})
+		return
+	}
+	if !isProductExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "product not found"})
+		return
+	}
+
+	supplies, err := sc.supplyRepository.SetProductSupplies(convertedId, request.Supplies)
+	if err != nil {
+		if errors.Is(err, repository.ErrSupplyNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message" : "supply not found", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to update product supplies", "details" : err.Error()})
+		return
+	}
+
+	response := ProductSuppliesResponse{
+		Message: "Successfully Updated Product Supplies",
+		Data: supplies,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Write 404 or 500 and return false when the supply can not be read
+func (sc *supplyController) supplyOf(ctx *gin.Context, id int) (*entity.Supply, bool) {
+	supply, err := sc.supplyRepository.GetSupply(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrSupplyNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message" : "supply not found"})
+			return supply, false
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get supply", "details" : err.Error()})
+		return supply, false
+	}
+	return supply, true
+}
+
+func validateSupply(supply *entity.Supply) error {
+	supply.Name = strings.TrimSpace(supply.Name)
+	supply.Unit = strings.TrimSpace(supply.Unit)
+	if supply.Name == "" {
+		return errors.New("name is required")
+	}
+	if supply.Unit == "" {
+		return errors.New("unit is required")
+	}
+	if supply.Reorder_level < 0 {
+		return errors.New("reorderLevel can not be negative")
+	}
+	return nil
+}
@@ -1,490 +1,955 @@
-package controller
-
-import (
-	"fmt"
-	"net/http"
-	"strconv"
-	"submission-project-enigma-laundry/entity"
-	"submission-project-enigma-laundry/repository"
-	"regexp"
-	"errors"
-	"github.com/gin-gonic/gin"
-)
-
-type TransactionController interface {
-	CreateTransaction(ctx *gin.Context)
-	GetTransaction(ctx *gin.Context)
-	ListTransaction(ctx *gin.Context)
-}
-
-type CreatedTransactionResponse struct {
-	Message string `json:"message"`
-	Data    struct {
-		Id         string `json:"id"`
-		BillDate   string `json:"billDate"`
-		EntryDate  string `json:"entryDate"`
-		FinishDate string `json:"finishDate"`
-		EmployeeId string `json:"employeeId"`
-		CustomerId string `json:"customerId"`
-		BillDetails []struct {
-			Id             string `json:"id"`
-			Transaction_id string    `json:"billId"`
-			Product_id     string `json:"productId"`
-			Product_price  int    `json:"productPrice"`
-			Qty            int    `json:"qty"`
-		} `json:"billDetails"`
-	} `json:"data"`
-}
-
-type TransactionResponse struct {
-	Message string `json:"message"`
-	Data    struct {
-		Id         string `json:"id"`
-		BillDate   string `json:"billDate"`
-		EntryDate  string `json:"entryDate"`
-		FinishDate string `json:"finishDate"`
-		Employee   entity.Employee `json:"employee"`
-		Customer   entity.Customer `json:"customer"`
-		BillDetails []struct {
-			Id             string `json:"id"`
-			Transaction_id string    `json:"billId"`
-			Product 	   entity.Product `json:"product"`
-			Product_price  int    `json:"productPrice"`
-			Qty            int    `json:"qty"`
-		} `json:"billDetails"`
-		Total_bill int `json:"totalBill"`
-	} `json:"data"`
-}
-
-type TransactionResponseSlice struct {
-	Message string `json:"message"`
-	Data    []struct {
-		Id         string `json:"id"`
-		BillDate   string `json:"billDate"`
-		EntryDate  string `json:"entryDate"`
-		FinishDate string `json:"finishDate"`
-		Employee   entity.Employee `json:"employee"`
-		Customer   entity.Customer `json:"customer"`
-		BillDetails []struct {
-			Id             string `json:"id"`
-			Transaction_id string    `json:"billId"`
-			Product 	   entity.Product `json:"product"`
-			Product_price  int    `json:"productPrice"`
-			Qty            int    `json:"qty"`
-		} `json:"billDetails"`
-		Total_bill int `json:"totalBill"`
-	} `json:"data"`
-}
-
-type transactionController struct {
-	customerRepository 		repository.CustomerRepository
-	employeeRepository 		repository.EmployeeRepository
-	productRepository 		repository.ProductRepository
-	transactionRepository 	repository.TransactionRepository
-}
-
-func NewTransactionController(cr repository.CustomerRepository,er repository.EmployeeRepository,pr repository.ProductRepository,tr repository.TransactionRepository) TransactionController {
-	return &transactionController{customerRepository: cr,employeeRepository: er,productRepository: pr,transactionRepository: tr}
-}
-
-func (tc *transactionController) CreateTransaction(ctx *gin.Context) {
-	var newTransaction entity.Transaction
-	err := ctx.ShouldBind(&newTransaction)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
-		return
-	}
-
-	converIdCustomer,err := strconv.Atoi(newTransaction.Customer_id) 
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert customer id. Make sure customer id is number", "details": err.Error()})
-		return
-	}
-
-	isCustomerExist,err := tc.customerRepository.IsCustomerExist(converIdCustomer,&newTransaction.Customer)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Customer", "details" : err.Error()})
-		return
-	}
-	if !isCustomerExist {
-		ctx.JSON(http.StatusNotFound, gin.H{"message" : "customer not found"})
-		return
-	}
-
-	converIdEmployee,err := strconv.Atoi(newTransaction.Employee_id) 
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert employee id. Make sure employee id is number", "details": err.Error()})
-		return
-	}
-
-	isEmployeeExist,err := tc.employeeRepository.IsEmployeeExist(converIdEmployee,&newTransaction.Employee)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Employee", "details" : err.Error()})
-		return
-	}
-	if !isEmployeeExist {
-		ctx.JSON(http.StatusNotFound, gin.H{"message" : "employee not found"})
-		return
-	}
-
-	for _, billDetail := range newTransaction.Bill_detail {
-		converIdProduct,err := strconv.Atoi(billDetail.Product_id)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert product id. Make sure product id is number", "details": err.Error()})
-			return
-		}
-
-		isProductExist,err := tc.productRepository.IsProductExist(converIdProduct,&billDetail.Product)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Product", "details" : err.Error()})
-			return
-		}
-		if !isProductExist {
-			ctx.JSON(http.StatusNotFound, gin.H{"message" : "product not found"})
-			return
-		}
-	}
-
-	isDateValid,err := isValidDate(newTransaction.Entry_date,newTransaction.Finish_date,newTransaction.Bill_date)
-	if !isDateValid && err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "entryDate, finishDate, billDate format is wrong", "details": err.Error()})
-		return
-	}
-
-	if newTransaction.Bill_date != newTransaction.Entry_date {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Bill date in future"})
-		return
-	}
-	
-
-	createdTransaction,err := tc.transactionRepository.CreateTransaction(&newTransaction) 
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to create transaction", "details" : err.Error()})
-		return
-	}
-
-	// Create The response struct 
-	var response CreatedTransactionResponse
-	response.Message = "Successfuly Create Transaction"
-	response.Data.Id = createdTransaction.Transaction_id
-	response.Data.BillDate = createdTransaction.Bill_date
-	response.Data.EntryDate = createdTransaction.Entry_date
-	response.Data.FinishDate = createdTransaction.Finish_date
-	response.Data.EmployeeId = createdTransaction.Employee_id
-	response.Data.CustomerId = createdTransaction.Customer_id
-
-	// Insert data into the nested BillDetails struct
-	for _, billDetail := range createdTransaction.Bill_detail {
-		response.Data.BillDetails = append(response.Data.BillDetails, struct{
-			Id string "json:\"id\""; 
-			Transaction_id string "json:\"billId\""; 
-			Product_id string "json:\"productId\""; 
-			Product_price int "json:\"productPrice\""; 
-			Qty int "json:\"qty\""
-		}{
-			Id: billDetail.Transaction_detail_id,
-			Transaction_id: billDetail.Transaction_id,
-			Product_id: billDetail.Product_id,
-			Product_price: billDetail.Product_price,
-			Qty: billDetail.Qty,
-		})
-	}
-	
-	ctx.JSON(http.StatusCreated, response)
-}
-
-func (tc *transactionController) GetTransaction(ctx *gin.Context) {
-	id,err := strconv.Atoi(ctx.Param("id_bill"))
-
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Failed convert id bill. Make sure id bill is number", "details" : err.Error()})
-		return
-	}
-
-	isTransactionExist,err := tc.transactionRepository.IsTransactionExist(id)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Transaction", "details" : err.Error()})
-		return
-	}
-	if !isTransactionExist {
-		ctx.JSON(http.StatusNotFound, gin.H{"message" : "transaction not found"})
-		return
-	}
-
-	isTransactionDetailExist,err := tc.transactionRepository.IsTransactionDetailExist(id)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Transaction Detail", "details" : err.Error()})
-		return
-	}
-	if !isTransactionDetailExist {
-		ctx.JSON(http.StatusNotFound, gin.H{"message" : "transaction detail not found"})
-		return
-	}
-
-	transaction := entity.Transaction{}
-	detailTransaction,err := tc.transactionRepository.GetTransaction(&transaction,id)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get transaction data", "details" : err.Error()})
-		return
-	}
-
-	// response struct 
-	var response TransactionResponse
-	response.Message = "Successfuly Get Transaction"
-
-	// Data Response
-	// Transaction
-	response.Data.Id = detailTransaction.Transaction_id
-	response.Data.BillDate = detailTransaction.Bill_date
-	response.Data.EntryDate = detailTransaction.Entry_date
-	response.Data.FinishDate = detailTransaction.Finish_date
-	response.Data.Total_bill = detailTransaction.Total_bill
-
-	// Employee
-	response.Data.Employee.Employee_id = detailTransaction.Employee.Employee_id
-	response.Data.Employee.Name = detailTransaction.Employee.Name
-	response.Data.Employee.Phone_number = detailTransaction.Employee.Phone_number
-	response.Data.Employee.Address = detailTransaction.Employee.Address
-
-	// Customer
-	response.Data.Customer.Customer_id = detailTransaction.Customer.Customer_id
-	response.Data.Customer.Name = detailTransaction.Customer.Name
-	response.Data.Customer.Phone_number = detailTransaction.Customer.Phone_number
-	response.Data.Customer.Address = detailTransaction.Customer.Address
-
-	// Bill Details
-	// Insert data into the nested BillDetails struct
-	for _, billDetail := range detailTransaction.Bill_detail {
-		response.Data.BillDetails = append(response.Data.BillDetails, struct{
-			Id string "json:\"id\""; 
-			Transaction_id string "json:\"billId\""; 
-			Product entity.Product "json:\"product\""; 
-			Product_price int "json:\"productPrice\""; 
-			Qty int "json:\"qty\""
-		}{
-			Id: billDetail.Transaction_detail_id,
-			Transaction_id: billDetail.Transaction_id,
-			// Product
-			Product: entity.Product{
-				Product_id: billDetail.Product.Product_id,
-				Product_name: billDetail.Product.Product_name,
-				Price: billDetail.Product.Price,
-				Unit: billDetail.Product.Unit,
-			},
-			Product_price: billDetail.Product_price,
-			Qty: billDetail.Qty,
-		})
-	}
-	
-	ctx.JSON(http.StatusOK, response)
-}
-
-func (tc *transactionController) ListTransaction(ctx *gin.Context) {
-	transactions := []entity.Transaction{}
-
-	var transactionQueryParam, transactionDetailQueryParam string
-
-    // Build the product query if productName is provided
-	startDate := ctx.Query("startDate")
-	endDate := ctx.Query("endDate")
-    productName := ctx.Query("productName")
-	// Build the transaction date query
-    if startDate != "" || endDate != "" || productName != ""{
-        transactionQueryParam = " WHERE " + buildDateQuery(startDate, endDate, productName)
-    }
-    if productName != "" {
-        transactionDetailQueryParam = " WHERE " + buildProductQuery(productName)
-    }
-	
-	rows,err := tc.transactionRepository.ListTransaction(transactionQueryParam)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get List Transaction", "details" : err.Error()})
-		return
-	}
-
-	defer rows.Close()
-
-	for rows.Next() {
-		transaction := entity.Transaction{}
-		err = rows.Scan(&transaction.Transaction_id,&transaction.Bill_date,&transaction.Entry_date,&transaction.Finish_date,&transaction.Employee.Employee_id,&transaction.Employee.Name,&transaction.Employee.Phone_number,&transaction.Employee.Address,&transaction.Customer.Customer_id,&transaction.Customer.Name,&transaction.Customer.Phone_number,&transaction.Customer.Address)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed scanning transaction", "details" : err.Error()})
-			return
-		}
-		transactions = append(transactions, transaction)
-	}
-
-	err = rows.Err()
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error encountred during iteration transaction", "details" : err.Error()})
-		return
-	}
-
-	rows,err = tc.transactionRepository.TransactionDetails(transactionDetailQueryParam)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get List Transaction detail", "details" : err.Error()})
-		return
-	}
-
-	transaction_details := []entity.Transaction_detail{}
-	for rows.Next() {
-		transaction_detail := entity.Transaction_detail{}
-		err = rows.Scan(&transaction_detail.Transaction_detail_id,&transaction_detail.Transaction_id,&transaction_detail.Product_price,&transaction_detail.Qty,&transaction_detail.Product.Product_id,&transaction_detail.Product.Product_name,&transaction_detail.Product.Price,&transaction_detail.Product.Unit)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed scanning transaction detail", "details" : err.Error()})
-			return
-		}
-		transaction_details = append(transaction_details, transaction_detail)
-	}
-	err = rows.Err()
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error encountred during iteration transaction", "details" : err.Error()})
-		return
-	}
-
-
-	for i := 0; i < len(transactions); i++ {
-		for j := 0; j < len(transaction_details); j++ {
-			// Check if the transaction_id matches
-			if transactions[i].Transaction_id == transaction_details[j].Transaction_id {
-				// Update the total bill
-				transactions[i].Total_bill += transaction_details[j].Product_price * transaction_details[j].Qty
-				
-				// Append the matching transaction detail to the Bill_detail slice
-				transactions[i].Bill_detail = append(transactions[i].Bill_detail, transaction_details[j])
-			}
-		}
-	}
-
-	var response TransactionResponseSlice
-	response.Message = "Successfully Get Transaction"
-
-	for _, transaction := range transactions {
-		// Reinitialize billDetails for each transaction to ensure it's empty
-		var billDetails []struct {
-			Id             string        `json:"id"`
-			Transaction_id string        `json:"billId"`
-			Product        entity.Product `json:"product"`
-			Product_price  int           `json:"productPrice"`
-			Qty            int           `json:"qty"`
-		}
-
-		// Loop through the bill details of the current transaction
-		for _, detail := range transaction.Bill_detail {
-			billDetails = append(billDetails, struct {
-				Id             string        `json:"id"`
-				Transaction_id string        `json:"billId"`
-				Product        entity.Product `json:"product"`
-				Product_price  int           `json:"productPrice"`
-				Qty            int           `json:"qty"`
-			}{
-				Id:             detail.Transaction_detail_id,
-				Transaction_id: detail.Transaction_id,
-				Product: entity.Product{
-					Product_id:   detail.Product.Product_id,
-					Product_name: detail.Product.Product_name,
-					Price:        detail.Product.Price,
-					Unit:         detail.Product.Unit,
-				},
-				Product_price: detail.Product_price,
-				Qty:           detail.Qty,
-			})
-		}
-
-		// Append the transaction and its bill details to the response
-		response.Data = append(response.Data, struct {
-			Id             string        `json:"id"`
-			BillDate       string        `json:"billDate"`
-			EntryDate      string        `json:"entryDate"`
-			FinishDate     string        `json:"finishDate"`
-			Employee       entity.Employee `json:"employee"`
-			Customer       entity.Customer `json:"customer"`
-			BillDetails    []struct {
-				Id             string        `json:"id"`
-				Transaction_id string        `json:"billId"`
-				Product        entity.Product `json:"product"`
-				Product_price  int           `json:"productPrice"`
-				Qty            int           `json:"qty"`
-			} `json:"billDetails"`
-			Total_bill int `json:"totalBill"`
-		}{
-			Id: transaction.Transaction_id,
-			BillDate: transaction.Bill_date,
-			EntryDate: transaction.Entry_date,
-			FinishDate: transaction.Finish_date,
-			Employee: entity.Employee{
-				Employee_id:   transaction.Employee.Employee_id,
-				Name:          transaction.Employee.Name,
-				Phone_number:  transaction.Employee.Phone_number,
-				Address:       transaction.Employee.Address,
-			},
-			Customer: entity.Customer{
-				Customer_id:   transaction.Customer.Customer_id,
-				Name:          transaction.Customer.Name,
-				Phone_number:  transaction.Customer.Phone_number,
-				Address:       transaction.Customer.Address,
-			},
-			BillDetails: billDetails, // Properly populated for each transaction
-			Total_bill:  transaction.Total_bill,
-		})
-	}
-
-	// If no data is found, return a "not found" response
-	if len(response.Data) == 0 {
-		ctx.JSON(http.StatusNotFound, gin.H{"message": "transaction not found"})
-		return
-	}
-
-	// Return the populated response
-	ctx.JSON(http.StatusOK, response)
-}
-
-func isValidDate(dates ...string) (bool,error) {
-	// Regular expression for dd/MM/yyyy format
-	var datePattern = `^(0[1-9]|[12][0-9]|3[01])-(0[1-9]|1[0-2])-\d{4}$`
-
-	// Compile the regular expression
-	re := regexp.MustCompile(datePattern)
-
-	// Iterate over each date and check if it matches the pattern
-	for _, date := range dates {
-		if !re.MatchString(date) {
-			err := errors.New("the entryDate / finishDate / billDate format is invalid make sure the date format is dd-mm-yyyy")
-			return false,err
-		}
-	}
-
-	// Return true if the date matches the pattern, false otherwise
-	return true,nil
-}
-
-
-func buildDateQuery(startDate, endDate, productName string) string {
-    query := ""
-    if startDate != "" {
-		if query != "" {
-            query += " AND"
-        }
-        query += fmt.Sprintf(" TO_DATE(t.entry_date, 'DD-MM-YY') >= TO_DATE('%s', 'DD-MM-YY')", startDate)
-    }
-    if endDate != "" {
-        if query != "" {
-            query += " AND"
-        }
-        query += fmt.Sprintf(" TO_DATE(t.finish_date, 'DD-MM-YY') <= TO_DATE('%s', 'DD-MM-YY')", endDate)
-    }
-	if productName != "" {
-		if query != "" {
-            query += " AND"
-        }
-		query += fmt.Sprintf(" p.product_name LIKE '%%%s%%'", productName)
-	}
-    return query
-}
-
-func buildProductQuery(productName string) string {
-    if productName != "" {
-        return fmt.Sprintf(" p.product_name LIKE '%%%s%%'", productName)
-    }
-    return ""
-}
\ No newline at end of file
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"submission-project-enigma-laundry/config"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/middleware"
+	"submission-project-enigma-laundry/repository"
+	"submission-project-enigma-laundry/utils"
+	"bytes"
+	"errors"
+	"strings"
+	"time"
+	"github.com/gin-gonic/gin"
+)
+
+type TransactionController interface {
+	CreateTransaction(ctx *gin.Context)
+	GetTransaction(ctx *gin.Context)
+	ListTransaction(ctx *gin.Context)
+	UpdateTransactionStatus(ctx *gin.Context)
+	CreatePayment(ctx *gin.Context)
+	UpdateTransaction(ctx *gin.Context)
+	CancelTransaction(ctx *gin.Context)
+	GetReceipt(ctx *gin.Context)
+}
+
+type TransactionRequest struct {
+	BillDate    string                      `json:"billDate"`
+	EntryDate   string                      `json:"entryDate"`
+	FinishDate  string                      `json:"finishDate"`
+	ServiceTier string                      `json:"serviceTier"`
+	PromoCode   string                      `json:"promoCode"`
+	RedeemPoints int                        `json:"redeemPoints"`
+	CustomerId  string                      `json:"customerId"`
+	BillDetails []entity.Transaction_detail `json:"billDetails"`
+	Deposit     *entity.Payment             `json:"deposit"`
+}
+
+type CreatedTransactionResponse struct {
+	Message string `json:"message"`
+	Data    struct {
+		Id         string `json:"id"`
+		BillNumber string `json:"billNumber"`
+		BillDate   string `json:"billDate"`
+		EntryDate  string `json:"entryDate"`
+		FinishDate string `json:"finishDate"`
+		Status     string `json:"status"`
+		ServiceTier string `json:"serviceTier"`
+		EmployeeId string `json:"employeeId"`
+		CustomerId string `json:"customerId"`
+		Deposit    *entity.Payment `json:"deposit,omitempty"`
+		Subtotal   int    `json:"subtotal"`
+		Surcharge  TransactionSurchargeResponse `json:"surcharge"`
+		PromoCode  string `json:"promoCode,omitempty"`
+		Discount   int    `json:"discount"`
+		Member     TransactionMemberResponse `json:"member"`
+		Tax        TransactionTaxResponse `json:"tax"`
+		TotalBill  int    `json:"totalBill"`
+		BillDetails []struct {
+			Id             string `json:"id"`
+			Transaction_id string    `json:"billId"`
+			Product_id     string `json:"productId"`
+			Product_price  int    `json:"productPrice"`
+			Qty            float64 `json:"qty"`
+			Line_total     int    `json:"lineTotal"`
+			Tax_inclusive  bool   `json:"taxInclusive"`
+			Subscription_id string `json:"subscriptionId,omitempty"`
+			Quota_used     float64 `json:"quotaUsed"`
+		} `json:"billDetails"`
+	} `json:"data"`
+}
+
+type TransactionBillDetailResponse struct {
+	Id             string         `json:"id"`
+	Transaction_id string         `json:"billId"`
+	Product        entity.Product `json:"product"`
+	Product_price  int            `json:"productPrice"`
+	Qty            float64        `json:"qty"`
+	Line_total     int            `json:"lineTotal"`
+	Tax_inclusive  bool           `json:"taxInclusive"`
+	Subscription_id string        `json:"subscriptionId,omitempty"`
+	Quota_used     float64        `json:"quotaUsed"`
+}
+
+// Service tier surcharge, shown apart from the bill details
+type TransactionSurchargeResponse struct {
+	ServiceTier string `json:"serviceTier"`
+	Type        string `json:"type"`
+	Value       int    `json:"value"`
+	Amount      int    `json:"amount"`
+}
+
+// Membership discount of the bill and the points redeemed on it
+type TransactionMemberResponse struct {
+	Tier            string `json:"tier"`
+	DiscountPercent int    `json:"discountPercent"`
+	Discount        int    `json:"discount"`
+	PointsRedeemed  int    `json:"pointsRedeemed"`
+	PointsDiscount  int    `json:"pointsDiscount"`
+}
+
+// PPN of the bill, base is the grand total without the tax
+type TransactionTaxResponse struct {
+	Rate   float64 `json:"rate"`
+	Base   int     `json:"base"`
+	Amount int     `json:"amount"`
+}
+
+type TransactionDataResponse struct {
+	Id            string                              `json:"id"`
+	BillNumber    string                              `json:"billNumber"`
+	BillDate      string                              `json:"billDate"`
+	EntryDate     string                              `json:"entryDate"`
+	FinishDate    string                              `json:"finishDate"`
+	Status        string                              `json:"status"`
+	ServiceTier   string                              `json:"serviceTier"`
+	CancelReason  string                              `json:"cancelReason,omitempty"`
+	CancelledAt   *time.Time                          `json:"cancelledAt,omitempty"`
+	Employee      entity.Employee                     `json:"employee"`
+	Customer      entity.Customer                     `json:"customer"`
+	BillDetails   []TransactionBillDetailResponse     `json:"billDetails"`
+	Subtotal      int                                 `json:"subtotal"`
+	Surcharge     TransactionSurchargeResponse        `json:"surcharge"`
+	PromoCode     string                              `json:"promoCode,omitempty"`
+	Discount      int                                 `json:"discount"`
+	Member        TransactionMemberResponse           `json:"member"`
+	Tax           TransactionTaxResponse              `json:"tax"`
+	Total_bill    int                                 `json:"totalBill"`
+	PaidAmount    int                                 `json:"paidAmount"`
+	Outstanding   int                                 `json:"outstanding"`
+	PaymentStatus string                              `json:"paymentStatus"`
+	Payments      []entity.Payment                    `json:"payments,omitempty"`
+	StatusHistory []entity.Transaction_status_history `json:"statusHistory,omitempty"`
+}
+
+type TransactionResponse struct {
+	Message string                  `json:"message"`
+	Data    TransactionDataResponse `json:"data"`
+}
+
+type TransactionResponseSlice = PagedResponse[TransactionDataResponse]
+
+type PaymentResponse struct {
+	Message string `json:"message"`
+	Data    struct {
+		Payment       entity.Payment `json:"payment"`
+		TotalBill     int            `json:"totalBill"`
+		PaidAmount    int            `json:"paidAmount"`
+		Outstanding   int            `json:"outstanding"`
+		PaymentStatus string         `json:"paymentStatus"`
+	} `json:"data"`
+}
+
+type TransactionStatusResponse struct {
+	Message string                            `json:"message"`
+	Data    entity.Transaction_status_history `json:"data"`
+}
+
+type transactionController struct {
+	customerRepository 		repository.CustomerRepository
+	employeeRepository 		repository.EmployeeRepository
+	productRepository 		repository.ProductRepository
+	transactionRepository 	repository.TransactionRepository
+	paymentRepository 		repository.PaymentRepository
+	serviceTierRepository 	repository.ServiceTierRepository
+}
+
+func NewTransactionController(cr repository.CustomerRepository,er repository.EmployeeRepository,pr repository.ProductRepository,tr repository.TransactionRepository,pyr repository.PaymentRepository,sr repository.ServiceTierRepository) TransactionController {
+	return &transactionController{customerRepository: cr,employeeRepository: er,productRepository: pr,transactionRepository: tr,paymentRepository: pyr,serviceTierRepository: sr}
+}
+
+func (tc *transactionController) CreateTransaction(ctx *gin.Context) {
+	var request TransactionRequest
+	err := ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	// Bill is always made by the logged in employee
+	converIdEmployee := ctx.GetInt(middleware.EmployeeIdKey)
+
+	newTransaction := entity.Transaction{
+		Customer_id: request.CustomerId,
+		Employee_id: strconv.Itoa(converIdEmployee),
+		Bill_detail: request.BillDetails,
+		Deposit:     request.Deposit,
+		Promo_code:  promoCode(request.PromoCode),
+		Points_redeemed: request.RedeemPoints,
+	}
+
+	if newTransaction.Points_redeemed < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "redeemPoints can not be negative"})
+		return
+	}
+
+	converIdCustomer,err := strconv.Atoi(newTransaction.Customer_id) 
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert customer id. Make sure customer id is number", "details": err.Error()})
+		return
+	}
+
+	isCustomerExist,err := tc.customerRepository.IsCustomerExist(converIdCustomer,&newTransaction.Customer)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Customer", "details" : err.Error()})
+		return
+	}
+	if !isCustomerExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "customer not found"})
+		return
+	}
+
+	isEmployeeExist,err := tc.employeeRepository.IsEmployeeExist(converIdEmployee,&newTransaction.Employee)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Employee", "details" : err.Error()})
+		return
+	}
+	if !isEmployeeExist {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"message" : "Unauthorized", "details" : "employee not found"})
+		return
+	}
+
+	for _, billDetail := range newTransaction.Bill_detail {
+		converIdProduct,err := strconv.Atoi(billDetail.Product_id)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert product id. Make sure product id is number", "details": err.Error()})
+			return
+		}
+
+		isProductExist,err := tc.productRepository.IsProductExist(converIdProduct,&billDetail.Product)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Product", "details" : err.Error()})
+			return
+		}
+		if !isProductExist {
+			ctx.JSON(http.StatusNotFound, gin.H{"message" : "product not found"})
+			return
+		}
+	}
+
+	if strings.TrimSpace(request.ServiceTier) == "" {
+		request.ServiceTier = entity.Tier_regular
+	}
+	serviceTier,err := tc.serviceTierRepository.GetServiceTier(request.ServiceTier)
+	if err != nil {
+		if errors.Is(err, repository.ErrServiceTierNotFound) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid service tier", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Service Tier", "details" : err.Error()})
+		return
+	}
+	// Surcharge is copied into the bill so later tier changes do not touch it
+	newTransaction.Service_tier = serviceTier.Code
+	newTransaction.Surcharge_type = serviceTier.Surcharge_type
+	newTransaction.Surcharge_value = serviceTier.Surcharge_value
+
+	newTransaction.Bill_date,err = parseDate(request.BillDate)
+	if err == nil {
+		newTransaction.Entry_date,err = parseDate(request.EntryDate)
+	}
+	if err == nil && strings.TrimSpace(request.FinishDate) != "" {
+		newTransaction.Finish_date,err = parseDate(request.FinishDate)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "entryDate, finishDate, billDate format is wrong", "details": err.Error()})
+		return
+	}
+
+	// Without finishDate the bill is done after the tier turnaround
+	if newTransaction.Finish_date.IsZero() {
+		newTransaction.Finish_date = serviceTier.FinishDate(newTransaction.Entry_date)
+	}
+
+	if !isSameDay(newTransaction.Bill_date, newTransaction.Entry_date) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Bill date in future"})
+		return
+	}
+
+	if newTransaction.Finish_date.Before(newTransaction.Entry_date) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "finishDate can not be before entryDate"})
+		return
+	}
+
+	if newTransaction.Deposit != nil {
+		err = validatePayment(newTransaction.Deposit)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid deposit", "details": err.Error()})
+			return
+		}
+	}
+
+	createdTransaction,err := tc.transactionRepository.CreateTransaction(&newTransaction) 
+	if err != nil {
+		if errors.Is(err, repository.ErrPaymentExceedsOutstanding) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Deposit is bigger than the bill", "details" : err.Error()})
+			return
+		}
+		if errors.Is(err, entity.ErrInvalidQty) || errors.Is(err, entity.ErrPackageOnBill) || errors.Is(err, entity.ErrProductInactive) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid bill detail", "details" : err.Error()})
+			return
+		}
+		if errors.Is(err, repository.ErrPromoNotFound) || errors.Is(err, entity.ErrPromoNotApplicable) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid promo code", "details" : err.Error()})
+			return
+		}
+		if errors.Is(err, repository.ErrNotEnoughPoints) || errors.Is(err, repository.ErrPointsExceedBill) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid redeemPoints", "details" : err.Error()})
+			return
+		}
+		if errors.Is(err, repository.ErrWalletInsufficient) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Wallet balance is not enough", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to create transaction", "details" : err.Error()})
+		return
+	}
+
+	// Create The response struct 
+	var response CreatedTransactionResponse
+	response.Message = "Successfuly Create Transaction"
+	response.Data.Id = createdTransaction.Transaction_id
+	response.Data.BillNumber = createdTransaction.Bill_number
+	response.Data.BillDate, response.Data.EntryDate, response.Data.FinishDate = formatTransactionDates(createdTransaction)
+	response.Data.Status = createdTransaction.Status
+	response.Data.ServiceTier = createdTransaction.Service_tier
+	response.Data.EmployeeId = createdTransaction.Employee_id
+	response.Data.CustomerId = createdTransaction.Customer_id
+	response.Data.Deposit = createdTransaction.Deposit
+	response.Data.Subtotal = createdTransaction.Subtotal
+	response.Data.Surcharge = newSurchargeResponse(createdTransaction)
+	response.Data.PromoCode = createdTransaction.Promo_code
+	response.Data.Discount = createdTransaction.Discount
+	response.Data.Member = newMemberResponse(createdTransaction)
+	response.Data.Tax = newTaxResponse(createdTransaction)
+	response.Data.TotalBill = createdTransaction.Total_bill
+
+	// Insert data into the nested BillDetails struct
+	for _, billDetail := range createdTransaction.Bill_detail {
+		response.Data.BillDetails = append(response.Data.BillDetails, struct{
+			Id string "json:\"id\""; 
+			Transaction_id string "json:\"billId\""; 
+			Product_id string "json:\"productId\""; 
+			Product_price int "json:\"productPrice\""; 
+			Qty float64 "json:\"qty\""; 
+			Line_total int "json:\"lineTotal\""; 
+			Tax_inclusive bool "json:\"taxInclusive\""; 
+			Subscription_id string "json:\"subscriptionId,omitempty\""; 
+			Quota_used float64 "json:\"quotaUsed\""
+		}{
+			Id: billDetail.Transaction_detail_id,
+			Transaction_id: billDetail.Transaction_id,
+			Product_id: billDetail.Product_id,
+			Product_price: billDetail.Product_price,
+			Qty: billDetail.Qty,
+			Line_total: billDetail.Line_total,
+			Tax_inclusive: billDetail.Tax_inclusive,
+			Subscription_id: billDetail.Subscription_id,
+			Quota_used: billDetail.Quota_used,
+		})
+	}
+	
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (tc *transactionController) GetTransaction(ctx *gin.Context) {
+	id,ok := tc.resolveBillId(ctx)
+	if !ok {
+		return
+	}
+
+	isTransactionExist,err := tc.transactionRepository.IsTransactionExist(id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Transaction", "details" : err.Error()})
+		return
+	}
+	if !isTransactionExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "transaction not found"})
+		return
+	}
+
+	isTransactionDetailExist,err := tc.transactionRepository.IsTransactionDetailExist(id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Transaction Detail", "details" : err.Error()})
+		return
+	}
+	if !isTransactionDetailExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "transaction detail not found"})
+		return
+	}
+
+	transaction := entity.Transaction{}
+	detailTransaction,err := tc.transactionRepository.GetTransaction(&transaction,id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get transaction data", "details" : err.Error()})
+		return
+	}
+
+	// response struct 
+	var response TransactionResponse
+	response.Message = "Successfuly Get Transaction"
+
+	response.Data = newTransactionDataResponse(detailTransaction)
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (tc *transactionController) ListTransaction(ctx *gin.Context) {
+	page, err := parsePageRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid paging", "details" : err.Error()})
+		return
+	}
+
+	filter := repository.TransactionFilter{
+		ProductName:   ctx.Query("productName"),
+		Status:        ctx.Query("status"),
+		PaymentStatus: ctx.Query("paymentStatus"),
+	}
+
+	if !parseDateFilter(ctx, &filter) {
+		return
+	}
+	if filter.Status != "" && !entity.IsValidStatus(filter.Status) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid status", "details": "status must be one of received, washing, ready, picked_up, cancelled"})
+		return
+	}
+	if filter.PaymentStatus != "" && !entity.IsValidPaymentStatus(filter.PaymentStatus) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid payment status", "details": "paymentStatus must be one of unpaid, partial, paid"})
+		return
+	}
+	if ctx.Query("customerId") != "" {
+		filter.CustomerId,err = strconv.Atoi(ctx.Query("customerId"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert customer id. Make sure customer id is number", "details": err.Error()})
+			return
+		}
+	}
+	if ctx.Query("employeeId") != "" {
+		filter.EmployeeId,err = strconv.Atoi(ctx.Query("employeeId"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert employee id. Make sure employee id is number", "details": err.Error()})
+			return
+		}
+	}
+	if ctx.Query("minTotal") != "" {
+		minTotal,err := strconv.Atoi(ctx.Query("minTotal"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert minTotal. Make sure minTotal is number", "details": err.Error()})
+			return
+		}
+		filter.MinTotal = &minTotal
+	}
+	if ctx.Query("maxTotal") != "" {
+		maxTotal,err := strconv.Atoi(ctx.Query("maxTotal"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert maxTotal. Make sure maxTotal is number", "details": err.Error()})
+			return
+		}
+		filter.MaxTotal = &maxTotal
+	}
+
+	transactions,total,err := tc.transactionRepository.ListTransaction(filter,page)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidPaging) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid paging", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get List Transaction", "details" : err.Error()})
+		return
+	}
+
+	transactions, paging := pageOf(page, total, transactions, func(transaction entity.Transaction) string { return transaction.Transaction_id })
+
+	var response TransactionResponseSlice
+	response.Message = "Successfully Get Transaction"
+	response.Paging = paging
+
+	for i := range transactions {
+		response.Data = append(response.Data, newTransactionDataResponse(&transactions[i]))
+	}
+
+	// If no data is found, return a "not found" response
+	if len(response.Data) == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "transaction not found"})
+		return
+	}
+
+	// Return the populated response
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (tc *transactionController) UpdateTransactionStatus(ctx *gin.Context) {
+	id,ok := tc.resolveBillId(ctx)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Status string `json:"status"`
+		Note   string `json:"note"`
+	}
+	err := ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	if !entity.IsValidStatus(request.Status) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid status", "details": "status must be one of received, washing, ready, picked_up, cancelled"})
+		return
+	}
+
+	// Washers move the order forward, only cashier and admin may cancel it
+	if request.Status == entity.Status_cancelled && !middleware.HasRole(ctx, entity.Role_admin, entity.Role_cashier) {
+		ctx.JSON(http.StatusForbidden, gin.H{"message": "Forbidden", "details": "requires role admin or cashier to cancel"})
+		return
+	}
+
+	if request.Status == entity.Status_cancelled && strings.TrimSpace(request.Note) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Cancel reason is required", "details": "fill note with the reason of cancellation"})
+		return
+	}
+
+	isTransactionExist,err := tc.transactionRepository.IsTransactionExist(id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Transaction", "details" : err.Error()})
+		return
+	}
+	if !isTransactionExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "transaction not found"})
+		return
+	}
+
+	history,err := tc.transactionRepository.UpdateTransactionStatus(id,request.Status,request.Note)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidStatusTransition) {
+			ctx.JSON(http.StatusConflict, gin.H{"message" : "Status can not be changed", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to update transaction status", "details" : err.Error()})
+		return
+	}
+
+	response := TransactionStatusResponse{
+		Message: "Successfully Update Transaction Status",
+		Data: *history,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (tc *transactionController) CreatePayment(ctx *gin.Context) {
+	id,ok := tc.resolveBillId(ctx)
+	if !ok {
+		return
+	}
+
+	var newPayment entity.Payment
+	err := ctx.ShouldBind(&newPayment)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	if newPayment.Payment_type == "" {
+		newPayment.Payment_type = entity.Payment_type_payment
+	}
+
+	err = validatePayment(&newPayment)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid payment", "details": err.Error()})
+		return
+	}
+
+	isTransactionExist,err := tc.transactionRepository.IsTransactionExist(id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Transaction", "details" : err.Error()})
+		return
+	}
+	if !isTransactionExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "transaction not found"})
+		return
+	}
+
+	transaction := entity.Transaction{}
+	createdPayment,err := tc.paymentRepository.CreatePayment(id,&newPayment,&transaction)
+	if err != nil {
+		if errors.Is(err, repository.ErrPaymentExceedsOutstanding) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Payment is bigger than the outstanding balance", "details" : err.Error()})
+			return
+		}
+		if errors.Is(err, repository.ErrTransactionCancelled) {
+			ctx.JSON(http.StatusConflict, gin.H{"message" : "Transaction is cancelled", "details" : err.Error()})
+			return
+		}
+		if errors.Is(err, repository.ErrWalletInsufficient) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Wallet balance is not enough", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to create payment", "details" : err.Error()})
+		return
+	}
+
+	var response PaymentResponse
+	response.Message = "Successfully Create Payment"
+	response.Data.Payment = *createdPayment
+	response.Data.TotalBill = transaction.Total_bill
+	response.Data.PaidAmount = transaction.Paid_amount
+	response.Data.Outstanding = transaction.Outstanding
+	response.Data.PaymentStatus = transaction.Payment_status
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (tc *transactionController) UpdateTransaction(ctx *gin.Context) {
+	id,ok := tc.resolveBillId(ctx)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		BillDetails []entity.Transaction_detail `json:"billDetails"`
+	}
+	err := ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	updateTransaction := entity.Transaction{Bill_detail: request.BillDetails}
+
+	if len(updateTransaction.Bill_detail) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Bill details can not be empty, cancel the transaction instead"})
+		return
+	}
+
+	for _, billDetail := range updateTransaction.Bill_detail {
+		if billDetail.Qty <= 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "qty must be greater than 0"})
+			return
+		}
+
+		converIdProduct,err := strconv.Atoi(billDetail.Product_id)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Failed convert product id. Make sure product id is number", "details": err.Error()})
+			return
+		}
+
+		isProductExist,err := tc.productRepository.IsProductExist(converIdProduct,&billDetail.Product)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Product", "details" : err.Error()})
+			return
+		}
+		if !isProductExist {
+			ctx.JSON(http.StatusNotFound, gin.H{"message" : "product not found"})
+			return
+		}
+	}
+
+	isTransactionExist,err := tc.transactionRepository.IsTransactionExist(id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Transaction", "details" : err.Error()})
+		return
+	}
+	if !isTransactionExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "transaction not found"})
+		return
+	}
+
+	_,err = tc.transactionRepository.UpdateTransaction(id,&updateTransaction)
+	if err != nil {
+		if errors.Is(err, repository.ErrTransactionNotEditable) || errors.Is(err, repository.ErrTotalBelowPaid) {
+			ctx.JSON(http.StatusConflict, gin.H{"message" : "Transaction can not be updated", "details" : err.Error()})
+			return
+		}
+		if errors.Is(err, repository.ErrBillDetailNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message" : "bill detail not found", "details" : err.Error()})
+			return
+		}
+		if errors.Is(err, entity.ErrInvalidQty) || errors.Is(err, entity.ErrPackageOnBill) || errors.Is(err, entity.ErrProductInactive) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid bill detail", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to update transaction", "details" : err.Error()})
+		return
+	}
+
+	transaction := entity.Transaction{}
+	detailTransaction,err := tc.transactionRepository.GetTransaction(&transaction,id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get transaction data", "details" : err.Error()})
+		return
+	}
+
+	var response TransactionResponse
+	response.Message = "Successfully Update Transaction"
+	response.Data = newTransactionDataResponse(detailTransaction)
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (tc *transactionController) CancelTransaction(ctx *gin.Context) {
+	id,ok := tc.resolveBillId(ctx)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Reason string `json:"reason"`
+	}
+	err := ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid Input","details" : err.Error()})
+		return
+	}
+
+	if strings.TrimSpace(request.Reason) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Cancel reason is required"})
+		return
+	}
+
+	isTransactionExist,err := tc.transactionRepository.IsTransactionExist(id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Transaction", "details" : err.Error()})
+		return
+	}
+	if !isTransactionExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "transaction not found"})
+		return
+	}
+
+	history,err := tc.transactionRepository.CancelTransaction(id,request.Reason)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidStatusTransition) {
+			ctx.JSON(http.StatusConflict, gin.H{"message" : "Transaction can not be cancelled", "details" : err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to cancel transaction", "details" : err.Error()})
+		return
+	}
+
+	response := TransactionStatusResponse{
+		Message: "Successfully Cancel Transaction",
+		Data: *history,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Receipt of the bill as PDF, ?format=58mm or 80mm (default) for thermal printer and a4 for invoice
+func (tc *transactionController) GetReceipt(ctx *gin.Context) {
+	id,ok := tc.resolveBillId(ctx)
+	if !ok {
+		return
+	}
+
+	format := ctx.DefaultQuery("format", utils.Receipt_80mm)
+	if !utils.IsValidReceiptFormat(format) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message" : "Invalid receipt format", "details" : utils.ErrInvalidReceiptFormat.Error()})
+		return
+	}
+
+	isTransactionExist,err := tc.transactionRepository.IsTransactionExist(id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Transaction", "details" : err.Error()})
+		return
+	}
+	if !isTransactionExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "transaction not found"})
+		return
+	}
+
+	transaction := entity.Transaction{}
+	detailTransaction,err := tc.transactionRepository.GetTransaction(&transaction,id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to get transaction data", "details" : err.Error()})
+		return
+	}
+
+	// Render into a buffer first so a failure can still be answered with JSON
+	var receipt bytes.Buffer
+	err = utils.RenderReceipt(&receipt, detailTransaction, config.LoadShopInfo(), format)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Failed to create receipt", "details" : err.Error()})
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`inline; filename="receipt-%s-%s.pdf"`, detailTransaction.Bill_number, format))
+	ctx.Data(http.StatusOK, "application/pdf", receipt.Bytes())
+}
+
+// The id_bill param is either the bill id or its bill number like EL-JKT-20261018-0042
+func (tc *transactionController) resolveBillId(ctx *gin.Context) (int, bool) {
+	param := ctx.Param("id_bill")
+
+	id,err := strconv.Atoi(param)
+	if err == nil {
+		return id, true
+	}
+
+	id,isBillNumberExist,err := tc.transactionRepository.GetTransactionIdByBillNumber(strings.ToUpper(param))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message" : "Error While Checking Transaction", "details" : err.Error()})
+		return id, false
+	}
+	if !isBillNumberExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"message" : "transaction not found"})
+		return id, false
+	}
+	return id, true
+}
+
+func newTransactionDataResponse(transaction *entity.Transaction) TransactionDataResponse {
+	data := TransactionDataResponse{
+		Id:            transaction.Transaction_id,
+		BillNumber:    transaction.Bill_number,
+		Status:        transaction.Status,
+		ServiceTier:   transaction.Service_tier,
+		CancelReason:  transaction.Cancel_reason,
+		CancelledAt:   transaction.Cancelled_at,
+		Employee:      transaction.Employee,
+		Customer:      transaction.Customer,
+		Subtotal:      transaction.Subtotal,
+		Surcharge:     newSurchargeResponse(transaction),
+		PromoCode:     transaction.Promo_code,
+		Discount:      transaction.Discount,
+		Member:        newMemberResponse(transaction),
+		Tax:           newTaxResponse(transaction),
+		Total_bill:    transaction.Total_bill,
+		PaidAmount:    transaction.Paid_amount,
+		Outstanding:   transaction.Outstanding,
+		PaymentStatus: transaction.Payment_status,
+		Payments:      transaction.Payments,
+		StatusHistory: transaction.Status_history,
+	}
+	data.BillDate, data.EntryDate, data.FinishDate = formatTransactionDates(transaction)
+
+	for _, billDetail := range transaction.Bill_detail {
+		data.BillDetails = append(data.BillDetails, TransactionBillDetailResponse{
+			Id:             billDetail.Transaction_detail_id,
+			Transaction_id: billDetail.Transaction_id,
+			Product:        billDetail.Product,
+			Product_price:  billDetail.Product_price,
+			Qty:            billDetail.Qty,
+			Line_total:     billDetail.Line_total,
+			Tax_inclusive:  billDetail.Tax_inclusive,
+			Subscription_id: billDetail.Subscription_id,
+			Quota_used:     billDetail.Quota_used,
+		})
+	}
+
+	return data
+}
+
+func newSurchargeResponse(transaction *entity.Transaction) TransactionSurchargeResponse {
+	return TransactionSurchargeResponse{
+		ServiceTier: transaction.Service_tier,
+		Type:        transaction.Surcharge_type,
+		Value:       transaction.Surcharge_value,
+		Amount:      transaction.Surcharge,
+	}
+}
+
+func newMemberResponse(transaction *entity.Transaction) TransactionMemberResponse {
+	return TransactionMemberResponse{
+		Tier:            transaction.Member_tier,
+		DiscountPercent: transaction.Member_discount_percent,
+		Discount:        transaction.Member_discount,
+		PointsRedeemed:  transaction.Points_redeemed,
+		PointsDiscount:  transaction.Points_discount,
+	}
+}
+
+func newTaxResponse(transaction *entity.Transaction) TransactionTaxResponse {
+	return TransactionTaxResponse{
+		Rate:   float64(transaction.Tax_rate) / 100,
+		Base:   transaction.Total_bill - transaction.Tax,
+		Amount: transaction.Tax,
+	}
+}
+
+// Accepted input layouts, ISO-8601 first then the legacy dd-mm-yyyy
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	time.DateOnly,
+	config.LegacyDateLayout,
+}
+
+// Read the startDate and endDate query params into the filter, false when a response was already sent
+func parseDateFilter(ctx *gin.Context, filter *repository.TransactionFilter) bool {
+	var err error
+	if ctx.Query("startDate") != "" {
+		filter.StartDate,err = parseDate(ctx.Query("startDate"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "startDate format is wrong", "details": err.Error()})
+			return false
+		}
+	}
+	if ctx.Query("endDate") != "" {
+		filter.EndDate,err = parseDate(ctx.Query("endDate"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "endDate format is wrong", "details": err.Error()})
+			return false
+		}
+		// A date without time covers the whole day
+		if filter.EndDate.Equal(startOfDay(filter.EndDate)) {
+			filter.EndDate = filter.EndDate.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+	}
+	return true
+}
+
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		date, err := time.ParseInLocation(layout, strings.TrimSpace(value), time.Local)
+		if err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date %q is invalid, use ISO-8601 (yyyy-mm-dd or yyyy-mm-ddThh:mm:ssZ) or dd-mm-yyyy", value)
+}
+
+func startOfDay(date time.Time) time.Time {
+	year, month, day := date.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+}
+
+func isSameDay(first time.Time, second time.Time) bool {
+	return startOfDay(first).Equal(startOfDay(second.In(first.Location())))
+}
+
+// Bill, entry and finish date formatted with the configured DATE_FORMAT
+func formatTransactionDates(transaction *entity.Transaction) (string, string, string) {
+	dateLayout, dateTimeLayout := config.DateFormat()
+	return transaction.Bill_date.Format(dateLayout), transaction.Entry_date.Format(dateTimeLayout), transaction.Finish_date.Format(dateTimeLayout)
+}
+
+func validatePayment(payment *entity.Payment) error {
+	if payment.Amount <= 0 {
+		return errors.New("amount must be greater than 0")
+	}
+	if !entity.IsValidPaymentMethod(payment.Method) {
+		return errors.New("method must be one of cash, transfer, e_wallet, wallet")
+	}
+	if payment.Payment_type != "" && !entity.IsValidPaymentType(payment.Payment_type) {
+		return errors.New("type must be one of deposit, payment")
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package entity
+
+// Group of products on the catalogue, ex : wash & fold, dry clean, ironing, add-ons.
+// Categories and their products are shown by Sort_order, inactive ones are left out of the catalogue
+type Category struct {
+	Category_id string `json:"id"`
+	Name string `json:"name"`
+	Description string `json:"description"`
+	Sort_order int `json:"sortOrder"`
+	Active bool `json:"active"`
+	Products []Product `json:"products,omitempty"`
+}
\ No newline at end of file
@@ -1,8 +1,31 @@
-package entity
-
-type Customer struct {
-	Customer_id string `json:"id"`
-	Name string `json:"name"`
-	Phone_number string `json:"phoneNumber"`
-	Address string `json:"address"`
+package entity
+
+import "time"
+
+type Customer struct {
+	Customer_id string `json:"id"`
+	Name string `json:"name"`
+	Phone_number string `json:"phoneNumber"`
+	Address string `json:"address"`
+	Deleted_at *time.Time `json:"deletedAt,omitempty"`
+}
+
+// Lifetime statistics of a customer, cancelled bills are left out
+type Customer_summary struct {
+	Customer_id string `json:"customerId"`
+	Visit_count int `json:"visitCount"`
+	Lifetime_spend int `json:"lifetimeSpend"`
+	Average_bill int `json:"averageBill"`
+	Outstanding int `json:"outstanding"`
+	First_visit *time.Time `json:"firstVisit"`
+	Last_visit *time.Time `json:"lastVisit"`
+	Favourite_products []Favourite_product `json:"favouriteProducts"`
+}
+
+// Product on the most bills of a customer
+type Favourite_product struct {
+	Product_id string `json:"productId"`
+	Product_name string `json:"productName"`
+	Bill_count int `json:"billCount"`
+	Qty float64 `json:"qty"`
 }
\ No newline at end of file
@@ -1,8 +1,15 @@
-package entity
-
-type Employee struct {
-	Employee_id string `json:"id"`
-	Name string `json:"name"`
-	Phone_number string `json:"phoneNumber"`
-	Address string `json:"address"`
-}
\ No newline at end of file
+package entity
+
+import "time"
+
+type Employee struct {
+	Employee_id string `json:"id"`
+	Name string `json:"name"`
+	Phone_number string `json:"phoneNumber"`
+	Address string `json:"address"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Password_hash string `json:"-"`
+	Roles []string `json:"roles,omitempty"`
+	Deleted_at *time.Time `json:"deletedAt,omitempty"`
+}
@@ -0,0 +1,23 @@
+package entity
+
+type Sort_field struct {
+	Field string
+	Desc  bool
+}
+
+// Paging asked by the client, Cursor_mode is used when the cursor query param is sent
+type Page_request struct {
+	Page        int
+	Size        int
+	Cursor      string
+	Cursor_mode bool
+	Sort        []Sort_field
+}
+
+type Paging struct {
+	Page        int    `json:"page,omitempty"`
+	Size        int    `json:"size"`
+	Total       int    `json:"total"`
+	Total_pages int    `json:"totalPages,omitempty"`
+	Next_cursor string `json:"nextCursor,omitempty"`
+}
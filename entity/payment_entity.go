@@ -0,0 +1,65 @@
+package entity
+
+import "time"
+
+// Payment method, wallet is paid from the prepaid balance of the customer
+const (
+	Payment_method_cash     = "cash"
+	Payment_method_transfer = "transfer"
+	Payment_method_e_wallet = "e_wallet"
+	Payment_method_wallet   = "wallet"
+)
+
+// Payment type, deposit is money received at drop-off
+const (
+	Payment_type_deposit = "deposit"
+	Payment_type_payment = "payment"
+)
+
+// Payment status of a bill
+const (
+	Payment_status_unpaid  = "unpaid"
+	Payment_status_partial = "partial"
+	Payment_status_paid    = "paid"
+)
+
+type Payment struct {
+	Payment_id     string    `json:"id"`
+	Transaction_id string    `json:"billId"`
+	Amount         int       `json:"amount"`
+	Method         string    `json:"method"`
+	Payment_type   string    `json:"type"`
+	Note           string    `json:"note"`
+	Paid_at        time.Time `json:"paidAt"`
+}
+
+func IsValidPaymentMethod(method string) bool {
+	switch method {
+	case Payment_method_cash, Payment_method_transfer, Payment_method_e_wallet, Payment_method_wallet:
+		return true
+	}
+	return false
+}
+
+func IsValidPaymentType(paymentType string) bool {
+	return paymentType == Payment_type_deposit || paymentType == Payment_type_payment
+}
+
+func IsValidPaymentStatus(status string) bool {
+	switch status {
+	case Payment_status_unpaid, Payment_status_partial, Payment_status_paid:
+		return true
+	}
+	return false
+}
+
+// Payment status based on bill total and the amount already paid
+func GetPaymentStatus(total int, paid int) string {
+	if paid <= 0 {
+		return Payment_status_unpaid
+	}
+	if paid < total {
+		return Payment_status_partial
+	}
+	return Payment_status_paid
+}
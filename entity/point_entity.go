@@ -0,0 +1,93 @@
+package entity
+
+import "time"
+
+// Points ledger entry types, reverse takes back the points earned on a cancelled bill
+// and refund gives back the points redeemed on it
+const (
+	Point_earn    = "earn"
+	Point_redeem  = "redeem"
+	Point_reverse = "reverse"
+	Point_refund  = "refund"
+)
+
+// One point for every Rp 1.000 of a paid bill, a redeemed point is worth Rp 10
+const (
+	Point_earn_unit = 1000
+	Point_value     = 10
+)
+
+// Membership tiers, reached by the points earned on paid bills
+const (
+	Membership_member = "member"
+	Membership_silver = "silver"
+	Membership_gold   = "gold"
+)
+
+type Membership_tier struct {
+	Code             string `json:"code"`
+	Min_points       int    `json:"minPoints"`
+	Discount_percent int    `json:"discountPercent"`
+}
+
+// Highest tier first
+var Membership_tiers = []Membership_tier{
+	{Code: Membership_gold, Min_points: 5000, Discount_percent: 10},
+	{Code: Membership_silver, Min_points: 1000, Discount_percent: 5},
+	{Code: Membership_member, Min_points: 0, Discount_percent: 0},
+}
+
+type Point_entry struct {
+	Point_id       string    `json:"id"`
+	Customer_id    string    `json:"customerId"`
+	Transaction_id string    `json:"billId,omitempty"`
+	Bill_number    string    `json:"billNumber,omitempty"`
+	Points         int       `json:"points"`
+	Entry_type     string    `json:"type"`
+	Note           string    `json:"note"`
+	Created_at     time.Time `json:"createdAt"`
+}
+
+type Customer_points struct {
+	Customer_id     string          `json:"customerId"`
+	Balance         int             `json:"balance"`
+	Lifetime_points int             `json:"lifetimePoints"`
+	Tier            Membership_tier `json:"tier"`
+	Next_tier       *Membership_tier `json:"nextTier,omitempty"`
+	Ledger          []Point_entry   `json:"ledger"`
+}
+
+func GetMembershipTier(lifetimePoints int) Membership_tier {
+	for _, tier := range Membership_tiers {
+		if lifetimePoints >= tier.Min_points {
+			return tier
+		}
+	}
+	return Membership_tiers[len(Membership_tiers)-1]
+}
+
+// Tier right above the given one, nil at the top
+func GetNextMembershipTier(code string) *Membership_tier {
+	for i, tier := range Membership_tiers {
+		if tier.Code == code && i > 0 {
+			next := Membership_tiers[i-1]
+			return &next
+		}
+	}
+	return nil
+}
+
+func GetEarnedPoints(totalBill int) int {
+	if totalBill <= 0 {
+		return 0
+	}
+	return totalBill / Point_earn_unit
+}
+
+// Member discount is rounded half up to whole rupiah
+func GetMemberDiscount(percent int, amount int) int {
+	if percent <= 0 || amount <= 0 {
+		return 0
+	}
+	return (amount*percent + 50) / 100
+}
\ No newline at end of file
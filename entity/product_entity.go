@@ -1,8 +1,102 @@
-package entity
-
-type Product struct {
-	Product_id string `json:"id"`
-	Product_name string `json:"name"`
-	Price int `json:"price"`
-	Unit string `json:"unit"`
-}
\ No newline at end of file
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Product pricing models
+const (
+	Pricing_per_kg    = "per_kg"
+	Pricing_per_piece = "per_piece"
+	Pricing_flat      = "flat"
+)
+
+// Product types, a package is sold as a subscription with a kg quota on the products it covers
+const (
+	Product_type_service = "service"
+	Product_type_package = "package"
+)
+
+// Returned when a bill detail qty does not fit the pricing model of its product
+var ErrInvalidQty = errors.New("invalid qty")
+
+// Returned when a package is put on a bill instead of being subscribed to
+var ErrPackageOnBill = errors.New("package can not be put on a bill")
+
+// Returned when an inactive product is put on a new bill detail
+var ErrProductInactive = errors.New("product is not active")
+
+type Product struct {
+	Product_id string `json:"id"`
+	Product_name string `json:"name"`
+	Price int `json:"price"`
+	Unit string `json:"unit"`
+	Pricing_model string `json:"pricingModel"`
+	Min_weight float64 `json:"minWeight"`
+	Tax_inclusive bool `json:"taxInclusive"`
+	Product_type string `json:"productType"`
+	Quota float64 `json:"quota,omitempty"`
+	Covered_product_ids []string `json:"coveredProductIds,omitempty"`
+	Category_id string `json:"categoryId"`
+	Description string `json:"description"`
+	Sort_order int `json:"sortOrder"`
+	Active bool `json:"active"`
+	Deleted_at *time.Time `json:"deletedAt,omitempty"`
+	// Date Price is charged from on update, the current price stays until then
+	Price_effective_from time.Time `json:"-"`
+}
+
+// Price of a product from Effective_from until the next one, Current is the one charged today
+type Product_price struct {
+	Product_price_id string `json:"id"`
+	Product_id string `json:"productId"`
+	Price int `json:"price"`
+	Effective_from time.Time `json:"effectiveFrom"`
+	Current bool `json:"current"`
+	Created_at time.Time `json:"createdAt"`
+}
+
+func IsValidProductType(productType string) bool {
+	return productType == Product_type_service || productType == Product_type_package
+}
+
+func IsValidPricingModel(pricingModel string) bool {
+	switch pricingModel {
+	case Pricing_per_kg, Pricing_per_piece, Pricing_flat:
+		return true
+	}
+	return false
+}
+
+// Charged amount of qty of product. Per kg takes weight with at most 2 decimals and
+// charges at least the minimum weight, per piece takes whole pieces and flat is
+// charged once whatever the size of the order
+func GetLineTotal(product Product, qty float64) (int, error) {
+	if qty <= 0 {
+		return 0, fmt.Errorf("%w, qty must be greater than 0", ErrInvalidQty)
+	}
+
+	switch product.Pricing_model {
+	case Pricing_per_kg:
+		if math.Abs(qty*100-math.Round(qty*100)) > 1e-6 {
+			return 0, fmt.Errorf("%w, weight of %s has at most 2 decimals", ErrInvalidQty, product.Product_name)
+		}
+		weight := math.Max(qty, product.Min_weight)
+		return int(math.Round(float64(product.Price) * weight)), nil
+	case Pricing_per_piece:
+		if qty != math.Trunc(qty) {
+			return 0, fmt.Errorf("%w, qty of %s must be a whole number", ErrInvalidQty, product.Product_name)
+		}
+		return product.Price * int(qty), nil
+	case Pricing_flat:
+		if qty != 1 {
+			return 0, fmt.Errorf("%w, qty of %s must be 1", ErrInvalidQty, product.Product_name)
+		}
+		return product.Price, nil
+	}
+
+	return 0, fmt.Errorf("unknown pricing model %q of %s", product.Pricing_model, product.Product_name)
+}
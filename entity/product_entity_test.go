@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetLineTotal(t *testing.T) {
+	perKg := Product{Product_name: "Cuci Kering", Price: 7000, Pricing_model: Pricing_per_kg, Min_weight: 3}
+	perPiece := Product{Product_name: "Bed Cover", Price: 25000, Pricing_model: Pricing_per_piece}
+	flat := Product{Product_name: "Antar Jemput", Price: 10000, Pricing_model: Pricing_flat}
+
+	tests := []struct {
+		name      string
+		product   Product
+		qty       float64
+		lineTotal int
+		err       error
+	}{
+		{"per kg below the minimum weight", perKg, 1.5, 21000, nil},
+		{"per kg at the minimum weight", perKg, 3, 21000, nil},
+		{"per kg above the minimum weight", perKg, 4.25, 29750, nil},
+		{"per kg without minimum weight", Product{Price: 7000, Pricing_model: Pricing_per_kg}, 1.5, 10500, nil},
+		{"per kg with 3 decimals", perKg, 4.125, 0, ErrInvalidQty},
+		{"per piece", perPiece, 2, 50000, nil},
+		{"per piece with a fractional qty", perPiece, 1.5, 0, ErrInvalidQty},
+		{"flat", flat, 1, 10000, nil},
+		{"flat with qty above 1", flat, 2, 0, ErrInvalidQty},
+		{"zero qty", perPiece, 0, 0, ErrInvalidQty},
+		{"negative qty", perKg, -1, 0, ErrInvalidQty},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lineTotal, err := GetLineTotal(test.product, test.qty)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+			if lineTotal != test.lineTotal {
+				t.Fatalf("expected line total %d, got %d", test.lineTotal, lineTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// How the discount value of a promo is applied
+const (
+	Discount_percent = "percent"
+	Discount_fixed   = "fixed"
+)
+
+// Returned when a promo exists but the bill does not meet its terms
+var ErrPromoNotApplicable = errors.New("promo code can not be applied")
+
+type Promo struct {
+	Promo_id       string    `json:"id"`
+	Code           string    `json:"code"`
+	Name           string    `json:"name"`
+	Discount_type  string    `json:"discountType"`
+	Discount_value int       `json:"discountValue"`
+	Max_discount   int       `json:"maxDiscount"`
+	Min_spend      int       `json:"minSpend"`
+	Starts_at      time.Time `json:"startsAt"`
+	Ends_at        time.Time `json:"endsAt"`
+	Usage_limit    int       `json:"usageLimit"`
+	Product_ids    []string  `json:"productIds"`
+}
+
+func IsValidDiscountType(discountType string) bool {
+	return discountType == Discount_percent || discountType == Discount_fixed
+}
+
+// A bill date has no time, the promo applies when it is valid at any time of that day
+func (promo Promo) IsActiveOn(day time.Time) bool {
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	return !promo.Ends_at.Before(dayStart) && promo.Starts_at.Before(dayStart.AddDate(0, 0, 1))
+}
+
+// Discount of the bill details, only the restricted products count when the promo has any.
+// Minimum spend is checked against the whole subtotal, the discount never exceeds the eligible lines
+func (promo Promo) GetDiscount(details []Transaction_detail) (int, error) {
+	subtotal, eligible := 0, 0
+	for _, detail := range details {
+		subtotal += detail.Line_total
+		if promo.appliesTo(detail.Product_id) {
+			eligible += detail.Line_total
+		}
+	}
+
+	if subtotal < promo.Min_spend {
+		return 0, fmt.Errorf("%w, minimum spend is %d", ErrPromoNotApplicable, promo.Min_spend)
+	}
+	if eligible == 0 {
+		return 0, fmt.Errorf("%w, no product of the bill is part of the promo", ErrPromoNotApplicable)
+	}
+
+	discount := promo.Discount_value
+	if promo.Discount_type == Discount_percent {
+		discount = (eligible*promo.Discount_value + 50) / 100
+		if promo.Max_discount > 0 && discount > promo.Max_discount {
+			discount = promo.Max_discount
+		}
+	}
+	if discount > eligible {
+		discount = eligible
+	}
+	return discount, nil
+}
+
+func (promo Promo) appliesTo(productId string) bool {
+	if len(promo.Product_ids) == 0 {
+		return true
+	}
+	for _, id := range promo.Product_ids {
+		if id == productId {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
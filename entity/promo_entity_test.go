@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPromoGetDiscount(t *testing.T) {
+	details := []Transaction_detail{
+		{Product_id: "1", Line_total: 30000},
+		{Product_id: "2", Line_total: 20000},
+	}
+
+	tests := []struct {
+		name     string
+		promo    Promo
+		discount int
+		err      error
+	}{
+		{"percent of the subtotal", Promo{Discount_type: Discount_percent, Discount_value: 10}, 5000, nil},
+		{"percent rounded", Promo{Discount_type: Discount_percent, Discount_value: 3}, 1500, nil},
+		{"percent capped by max discount", Promo{Discount_type: Discount_percent, Discount_value: 20, Max_discount: 7500}, 7500, nil},
+		{"percent below max discount", Promo{Discount_type: Discount_percent, Discount_value: 10, Max_discount: 7500}, 5000, nil},
+		{"fixed", Promo{Discount_type: Discount_fixed, Discount_value: 12000}, 12000, nil},
+		{"fixed ignores max discount", Promo{Discount_type: Discount_fixed, Discount_value: 12000, Max_discount: 5000}, 12000, nil},
+		{"fixed never exceeds the bill", Promo{Discount_type: Discount_fixed, Discount_value: 80000}, 50000, nil},
+		{"min spend met", Promo{Discount_type: Discount_fixed, Discount_value: 5000, Min_spend: 50000}, 5000, nil},
+		{"min spend not met", Promo{Discount_type: Discount_fixed, Discount_value: 5000, Min_spend: 50001}, 0, ErrPromoNotApplicable},
+		{"percent of the restricted product", Promo{Discount_type: Discount_percent, Discount_value: 10, Product_ids: []string{"2"}}, 2000, nil},
+		{"fixed capped by the restricted product", Promo{Discount_type: Discount_fixed, Discount_value: 25000, Product_ids: []string{"2"}}, 20000, nil},
+		{"min spend counts the whole bill", Promo{Discount_type: Discount_fixed, Discount_value: 5000, Min_spend: 40000, Product_ids: []string{"2"}}, 5000, nil},
+		{"no product of the bill", Promo{Discount_type: Discount_percent, Discount_value: 10, Product_ids: []string{"3"}}, 0, ErrPromoNotApplicable},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			discount, err := test.promo.GetDiscount(details)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+			if discount != test.discount {
+				t.Fatalf("expected discount %d, got %d", test.discount, discount)
+			}
+		})
+	}
+}
+
+func TestPromoIsActiveOn(t *testing.T) {
+	promo := Promo{
+		Starts_at: time.Date(2024, 10, 1, 10, 0, 0, 0, time.UTC),
+		Ends_at:   time.Date(2024, 10, 31, 23, 59, 59, 0, time.UTC),
+	}
+
+	tests := []struct {
+		billDate time.Time
+		active   bool
+	}{
+		{time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2024, 10, 31, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, test := range tests {
+		if promo.IsActiveOn(test.billDate) != test.active {
+			t.Fatalf("bill date %s expected active %v", test.billDate.Format(time.DateOnly), test.active)
+		}
+	}
+}
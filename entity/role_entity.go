@@ -0,0 +1,21 @@
+package entity
+
+// Employee roles
+const (
+	Role_admin   = "admin"
+	Role_cashier = "cashier"
+	Role_washer  = "washer"
+)
+
+type Role struct {
+	Role_id string `json:"id"`
+	Name    string `json:"name"`
+}
+
+func IsValidRole(role string) bool {
+	switch role {
+	case Role_admin, Role_cashier, Role_washer:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+// Default service tiers
+const (
+	Tier_regular  = "regular"
+	Tier_express  = "express"
+	Tier_same_day = "same_day"
+)
+
+// How the surcharge value of a service tier is applied
+const (
+	Surcharge_percent = "percent"
+	Surcharge_fixed   = "fixed"
+)
+
+type Service_tier struct {
+	Service_tier_id  string `json:"id"`
+	Code             string `json:"code"`
+	Name             string `json:"name"`
+	Surcharge_type   string `json:"surchargeType"`
+	Surcharge_value  int    `json:"surchargeValue"`
+	Turnaround_hours int    `json:"turnaroundHours"`
+}
+
+func IsValidSurchargeType(surchargeType string) bool {
+	switch surchargeType {
+	case Surcharge_percent, Surcharge_fixed:
+		return true
+	}
+	return false
+}
+
+// Percent surcharge is rounded half up to whole rupiah
+func GetSurcharge(surchargeType string, value int, subtotal int) int {
+	switch surchargeType {
+	case Surcharge_percent:
+		return (subtotal*value + 50) / 100
+	case Surcharge_fixed:
+		return value
+	}
+	return 0
+}
+
+func (tier Service_tier) FinishDate(entryDate time.Time) time.Time {
+	return entryDate.Add(time.Duration(tier.Turnaround_hours) * time.Hour)
+}
\ No newline at end of file
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"math"
+	"time"
+)
+
+// Subscription of a customer to a package, the quota is the kg covered from start until end date
+type Subscription struct {
+	Subscription_id     string    `json:"id"`
+	Customer_id         string    `json:"customerId"`
+	Package_id          string    `json:"packageId"`
+	Package_name        string    `json:"packageName"`
+	Start_date          time.Time `json:"startDate"`
+	End_date            time.Time `json:"endDate"`
+	Quota               float64   `json:"quota"`
+	Used                float64   `json:"used"`
+	Price               int       `json:"price"`
+	Covered_product_ids []string  `json:"coveredProductIds"`
+}
+
+func (subscription Subscription) Remaining() float64 {
+	return math.Max(0, math.Round((subscription.Quota-subscription.Used)*100)/100)
+}
+
+// Active on every day from start until end date
+func (subscription Subscription) IsActive(at time.Time) bool {
+	day := at.Format(time.DateOnly)
+	return day >= subscription.Start_date.Format(time.DateOnly) && day <= subscription.End_date.Format(time.DateOnly)
+}
+
+// Weight of qty covered by the remaining quota, rounded to 2 decimals like a bill detail weight
+func GetCoveredQty(qty float64, remaining float64) float64 {
+	if qty <= 0 || remaining <= 0 {
+		return 0
+	}
+	return math.Round(math.Min(qty, remaining)*100) / 100
+}
+
+// Weight above the covered quota is charged at the product price, minimum weight is not charged
+// again on a bill detail the package already covers
+func GetOverageLineTotal(product Product, qty float64, covered float64) int {
+	if covered >= qty {
+		return 0
+	}
+	return int(math.Round(float64(product.Price) * (qty - covered)))
+}
\ No newline at end of file
@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+// Stock movement types, usage is taken by a bill and return gives it back when the bill
+// is edited or cancelled. Restock and adjustment are entered by hand
+const (
+	Stock_usage      = "usage"
+	Stock_return     = "return"
+	Stock_restock    = "restock"
+	Stock_adjustment = "adjustment"
+)
+
+// Consumable used by the services, ex : detergent, softener, plastic bags.
+// Low_stock is set when stock is at or below Reorder_level
+type Supply struct {
+	Supply_id     string  `json:"id"`
+	Name          string  `json:"name"`
+	Unit          string  `json:"unit"`
+	Stock         float64 `json:"stock"`
+	Reorder_level float64 `json:"reorderLevel"`
+	Low_stock     bool    `json:"lowStock"`
+}
+
+// Bill of materials line, the supply used by one unit of the product qty
+type Product_supply struct {
+	Product_id     string  `json:"productId"`
+	Supply_id      string  `json:"supplyId"`
+	Supply_name    string  `json:"supplyName"`
+	Unit           string  `json:"unit"`
+	Usage_per_unit float64 `json:"usagePerUnit"`
+}
+
+type Stock_movement struct {
+	Stock_movement_id string    `json:"id"`
+	Supply_id         string    `json:"supplyId"`
+	Transaction_id    string    `json:"billId,omitempty"`
+	Bill_number       string    `json:"billNumber,omitempty"`
+	Employee_id       string    `json:"employeeId,omitempty"`
+	Change            float64   `json:"change"`
+	Stock_after       float64   `json:"stockAfter"`
+	Movement_type     string    `json:"type"`
+	Note              string    `json:"note"`
+	Created_at        time.Time `json:"createdAt"`
+}
+
+func IsValidStockAdjustment(movementType string) bool {
+	switch movementType {
+	case Stock_restock, Stock_adjustment:
+		return true
+	}
+	return false
+}
\ No newline at end of file
@@ -0,0 +1,28 @@
+package entity
+
+// Tax rates are kept in basis points, 1100 is 11%
+const Tax_rate_scale = 10000
+
+// Tax of the amount to pay before tax, with the part of it that is added on top.
+// The amount is split over tax inclusive and exclusive products by their share of the subtotal,
+// inclusive products already carry their tax and exclusive products get it added
+func GetTax(amount int, subtotal int, inclusiveSubtotal int, rate int) (int, int) {
+	if rate <= 0 || amount <= 0 {
+		return 0, 0
+	}
+
+	inclusive := 0
+	if subtotal > 0 {
+		inclusive = divRound(amount*inclusiveSubtotal, subtotal)
+	}
+	exclusive := amount - inclusive
+
+	inclusiveTax := divRound(inclusive*rate, Tax_rate_scale+rate)
+	exclusiveTax := divRound(exclusive*rate, Tax_rate_scale)
+	return inclusiveTax + exclusiveTax, exclusiveTax
+}
+
+// Division rounded half up to whole rupiah
+func divRound(numerator int, denominator int) int {
+	return (2*numerator + denominator) / (2 * denominator)
+}
\ No newline at end of file
@@ -1,10 +1,14 @@
-package entity
-
-type Transaction_detail struct {
-	Transaction_detail_id 	string `json:"id"`
-	Transaction_id 			string `json:"billId"`
-	Product 				Product `json:"product"`
-	Product_id 				string `json:"productId"`
-	Product_price 			int `json:"productPrice"`
-	Qty 					int `json:"qty"`
+package entity
+
+type Transaction_detail struct {
+	Transaction_detail_id 	string `json:"id"`
+	Transaction_id 			string `json:"billId"`
+	Product 				Product `json:"product"`
+	Product_id 				string `json:"productId"`
+	Product_price 			int `json:"productPrice"`
+	Qty 					float64 `json:"qty"`
+	Line_total 				int `json:"lineTotal"`
+	Tax_inclusive 			bool `json:"taxInclusive"`
+	Subscription_id 		string `json:"subscriptionId,omitempty"`
+	Quota_used 				float64 `json:"quotaUsed"`
 }
\ No newline at end of file
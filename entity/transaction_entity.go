@@ -1,15 +1,46 @@
-package entity
-
-
-type Transaction struct {
-	Transaction_id     	string				`json:"id"`
-	Customer_id        	string 				`json:"customerId"`
-	Employee_id        	string 				`json:"employeeId"`
-	Bill_date          	string 				`json:"billDate"`
-	Entry_date         	string 				`json:"entryDate"`
-	Finish_date        	string 				`json:"finishDate"`
-	Employee 			Employee 			`json:"employee"`
-	Customer 			Customer			`json:"customer"`
-	Bill_detail 		[]Transaction_detail  `json:"billDetails"`
-	Total_bill			int					`json:"totalBill"`
+package entity
+
+import "time"
+
+type Transaction struct {
+	Transaction_id     	string				`json:"id"`
+	Bill_number        	string				`json:"billNumber"`
+	Customer_id        	string 				`json:"customerId"`
+	Employee_id        	string 				`json:"employeeId"`
+	Bill_date          	time.Time 			`json:"billDate"`
+	Entry_date         	time.Time 			`json:"entryDate"`
+	Finish_date        	time.Time 			`json:"finishDate"`
+	Status 				string 				`json:"status"`
+	Service_tier 		string 				`json:"serviceTier"`
+	Surcharge_type 		string 				`json:"surchargeType"`
+	Surcharge_value 	int 				`json:"surchargeValue"`
+	Promo_id 			string 				`json:"-"`
+	Promo_code 			string 				`json:"promoCode"`
+	Cancel_reason 		string 				`json:"cancelReason"`
+	Cancelled_at 		*time.Time 			`json:"cancelledAt"`
+	Employee 			Employee 			`json:"employee"`
+	Customer 			Customer			`json:"customer"`
+	Bill_detail 		[]Transaction_detail  `json:"billDetails"`
+	Subtotal 			int 				`json:"subtotal"`
+	Surcharge 			int 				`json:"surcharge"`
+	Discount 			int 				`json:"discount"`
+	Member_tier 		string 				`json:"memberTier"`
+	Member_discount_percent int 			`json:"memberDiscountPercent"`
+	Member_discount 	int 				`json:"memberDiscount"`
+	Points_redeemed 	int 				`json:"pointsRedeemed"`
+	Points_discount 	int 				`json:"pointsDiscount"`
+	Tax_rate 			int 				`json:"taxRate"`
+	Tax 				int 				`json:"tax"`
+	Total_bill			int					`json:"totalBill"`
+	Paid_amount 		int 				`json:"paidAmount"`
+	Outstanding 		int 				`json:"outstanding"`
+	Payment_status 		string 				`json:"paymentStatus"`
+	Deposit 			*Payment 			`json:"deposit"`
+	Payments 			[]Payment 			`json:"payments"`
+	Status_history 		[]Transaction_status_history `json:"statusHistory"`
+}
+
+// What the customer pays before the added tax
+func (transaction Transaction) AmountBeforeTax() int {
+	return transaction.Subtotal + transaction.Surcharge - transaction.Discount - transaction.Member_discount - transaction.Points_discount
 }
\ No newline at end of file
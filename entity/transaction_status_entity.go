@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+// Laundry order status
+const (
+	Status_received  = "received"
+	Status_washing   = "washing"
+	Status_ready     = "ready"
+	Status_picked_up = "picked_up"
+	Status_cancelled = "cancelled"
+)
+
+// Allowed next status for every status, a status without entry is final
+var statusTransitions = map[string][]string{
+	Status_received: {Status_washing, Status_cancelled},
+	Status_washing:  {Status_ready, Status_cancelled},
+	Status_ready:    {Status_picked_up, Status_cancelled},
+}
+
+type Transaction_status_history struct {
+	History_id     string    `json:"id"`
+	Transaction_id string    `json:"billId"`
+	From_status    string    `json:"fromStatus"`
+	To_status      string    `json:"toStatus"`
+	Note           string    `json:"note"`
+	Changed_at     time.Time `json:"changedAt"`
+}
+
+func IsValidStatus(status string) bool {
+	switch status {
+	case Status_received, Status_washing, Status_ready, Status_picked_up, Status_cancelled:
+		return true
+	}
+	return false
+}
+
+func CanChangeStatus(from string, to string) bool {
+	for _, next := range statusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package entity
+
+import "testing"
+
+func TestCanChangeStatus(t *testing.T) {
+	tests := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{Status_received, Status_washing, true},
+		{Status_received, Status_cancelled, true},
+		{Status_washing, Status_ready, true},
+		{Status_washing, Status_cancelled, true},
+		{Status_ready, Status_picked_up, true},
+		{Status_ready, Status_cancelled, true},
+
+		{Status_received, Status_ready, false},
+		{Status_received, Status_picked_up, false},
+		{Status_received, Status_received, false},
+		{Status_washing, Status_received, false},
+		{Status_ready, Status_washing, false},
+		{Status_picked_up, Status_washing, false},
+		{Status_picked_up, Status_cancelled, false},
+		{Status_cancelled, Status_received, false},
+		{Status_cancelled, Status_washing, false},
+		{Status_cancelled, Status_ready, false},
+		{Status_cancelled, Status_picked_up, false},
+
+		{"unknown", Status_washing, false},
+		{Status_received, "unknown", false},
+		{"", "", false},
+	}
+
+	for _, test := range tests {
+		if CanChangeStatus(test.from, test.to) != test.allowed {
+			t.Fatalf("%q to %q expected allowed %v", test.from, test.to, test.allowed)
+		}
+	}
+}
+
+func TestIsValidStatus(t *testing.T) {
+	for _, status := range []string{Status_received, Status_washing, Status_ready, Status_picked_up, Status_cancelled} {
+		if !IsValidStatus(status) {
+			t.Fatalf("%q expected valid", status)
+		}
+	}
+	for _, status := range []string{"", "done", "Received", "picked up"} {
+		if IsValidStatus(status) {
+			t.Fatalf("%q expected invalid", status)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+// Wallet ledger entry types, a debit pays a bill or a subscription from the wallet and refund gives it
+// back when the bill is cancelled. Merge moves the balance of a duplicate customer
+const (
+	Wallet_top_up = "top_up"
+	Wallet_debit  = "debit"
+	Wallet_refund = "refund"
+	Wallet_merge  = "merge"
+)
+
+type Wallet_entry struct {
+	Wallet_entry_id string    `json:"id"`
+	Customer_id     string    `json:"customerId"`
+	Transaction_id  string    `json:"billId,omitempty"`
+	Bill_number     string    `json:"billNumber,omitempty"`
+	Payment_id      string    `json:"paymentId,omitempty"`
+	Amount          int       `json:"amount"`
+	Balance_after   int       `json:"balanceAfter"`
+	Entry_type      string    `json:"type"`
+	Method          string    `json:"method,omitempty"`
+	Note            string    `json:"note"`
+	Created_at      time.Time `json:"createdAt"`
+}
+
+type Customer_wallet struct {
+	Customer_id string         `json:"customerId"`
+	Balance     int            `json:"balance"`
+	Ledger      []Wallet_entry `json:"ledger"`
+}
+
+// Top up is paid with money, never from the wallet itself
+func IsValidTopUpMethod(method string) bool {
+	return method != Payment_method_wallet && IsValidPaymentMethod(method)
+}
\ No newline at end of file
@@ -1,42 +1,70 @@
-package main
-
-import (
-	"submission-project-enigma-laundry/config"
-	"submission-project-enigma-laundry/controller"
-	"submission-project-enigma-laundry/repository"
-	"submission-project-enigma-laundry/routes"
-
-	"github.com/gin-gonic/gin"
-)
-
-func main() {
-	// Connect into the database
-	db := config.ConnectDb()
-
-	defer db.Close()
-
-	var (
-		// Implement Dependency Injection
-		// Repository
-		customerRepository repository.CustomerRepository = repository.NewCustomerRepo(db)
-		employeeRepository repository.EmployeeRepository = repository.NewEmployeeRepo(db)
-		productRepository repository.ProductRepository = repository.NewProductRepo(db)
-		transactionRepository repository.TransactionRepository = repository.NewTransactionRepo(db)
-
-		// Controller
-		customerController controller.CustomerController = controller.NewCustomerController(customerRepository)
-		employeeController controller.EmployeeController = controller.NewEmployeeController(employeeRepository)
-		productController controller.ProductController = controller.NewProductController(productRepository)
-		transactionController controller.TransactionController = controller.NewTransactionController(customerRepository,employeeRepository,productRepository,transactionRepository)
-	)
-
-	server := gin.Default()
-
-	// Routes
-	routes.Customer(server,customerController)
-	routes.Employee(server,employeeController)
-	routes.Product(server,productController)
-	routes.Transaction(server,transactionController)
-
-	server.Run(":8080")
+package main
+
+import (
+	"log"
+	"submission-project-enigma-laundry/config"
+	"submission-project-enigma-laundry/controller"
+	"submission-project-enigma-laundry/middleware"
+	"submission-project-enigma-laundry/repository"
+	"submission-project-enigma-laundry/routes"
+
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	// Connect into the database
+	db := config.ConnectDb()
+
+	defer db.Close()
+
+	jwtConfig, err := config.LoadJwtConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var (
+		// Implement Dependency Injection
+		// Repository
+		customerRepository repository.CustomerRepository = repository.NewCustomerRepo(db)
+		employeeRepository repository.EmployeeRepository = repository.NewEmployeeRepo(db)
+		productRepository repository.ProductRepository = repository.NewProductRepo(db)
+		transactionRepository repository.TransactionRepository = repository.NewTransactionRepo(db,config.BranchCode(),config.TaxRate(),config.StockDeductedOnFinish())
+		paymentRepository repository.PaymentRepository = repository.NewPaymentRepo(db)
+		serviceTierRepository repository.ServiceTierRepository = repository.NewServiceTierRepo(db)
+		promoRepository repository.PromoRepository = repository.NewPromoRepo(db)
+		pointRepository repository.PointRepository = repository.NewPointRepo(db)
+		walletRepository repository.WalletRepository = repository.NewWalletRepo(db)
+		subscriptionRepository repository.SubscriptionRepository = repository.NewSubscriptionRepo(db)
+		categoryRepository repository.CategoryRepository = repository.NewCategoryRepo(db)
+		supplyRepository repository.SupplyRepository = repository.NewSupplyRepo(db)
+
+		// Controller
+		customerController controller.CustomerController = controller.NewCustomerController(customerRepository,pointRepository,walletRepository,subscriptionRepository,transactionRepository)
+		employeeController controller.EmployeeController = controller.NewEmployeeController(employeeRepository)
+		productController controller.ProductController = controller.NewProductController(productRepository,categoryRepository)
+		transactionController controller.TransactionController = controller.NewTransactionController(customerRepository,employeeRepository,productRepository,transactionRepository,paymentRepository,serviceTierRepository)
+		serviceTierController controller.ServiceTierController = controller.NewServiceTierController(serviceTierRepository)
+		promoController controller.PromoController = controller.NewPromoController(promoRepository,productRepository)
+		categoryController controller.CategoryController = controller.NewCategoryController(categoryRepository)
+		supplyController controller.SupplyController = controller.NewSupplyController(supplyRepository,productRepository)
+		authController controller.AuthController = controller.NewAuthController(employeeRepository,jwtConfig)
+
+		// Middleware
+		authMiddleware middleware.AuthMiddleware = middleware.NewAuthMiddleware(jwtConfig)
+	)
+
+	server := gin.Default()
+
+	// Routes
+	routes.Auth(server,authController)
+	routes.Customer(server,customerController,authMiddleware)
+	routes.Employee(server,employeeController,authMiddleware)
+	routes.Product(server,productController,authMiddleware)
+	routes.Transaction(server,transactionController,authMiddleware)
+	routes.ServiceTier(server,serviceTierController,authMiddleware)
+	routes.Promo(server,promoController,authMiddleware)
+	routes.Category(server,categoryController,authMiddleware)
+	routes.Supply(server,supplyController,authMiddleware)
+
+	server.Run(":8080")
 }
\ No newline at end of file
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"submission-project-enigma-laundry/config"
+	"submission-project-enigma-laundry/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Key of the logged in employee id and roles in gin context
+const (
+	EmployeeIdKey = "employeeId"
+	RolesKey      = "roles"
+)
+
+type AuthMiddleware interface {
+	RequireToken() gin.HandlerFunc
+	RequireRole(roles ...string) gin.HandlerFunc
+}
+
+type authMiddleware struct {
+	jwtConfig config.JwtConfig
+}
+
+func NewAuthMiddleware(jwtConfig config.JwtConfig) AuthMiddleware {
+	return &authMiddleware{jwtConfig: jwtConfig}
+}
+
+// Reject the request unless it carries a valid access token in "Authorization: Bearer <token>"
+func (am *authMiddleware) RequireToken() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		header := ctx.GetHeader("Authorization")
+		tokenString, found := strings.CutPrefix(header, "Bearer ")
+		if !found || tokenString == "" {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message" : "Unauthorized", "details" : "missing bearer token"})
+			return
+		}
+
+		claims, err := utils.ParseToken(am.jwtConfig.Secret, tokenString, utils.Token_access)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message" : "Unauthorized", "details" : err.Error()})
+			return
+		}
+
+		ctx.Set(EmployeeIdKey, claims.Employee_id)
+		ctx.Set(RolesKey, claims.Roles)
+		ctx.Next()
+	}
+}
+
+// Reject the request unless the logged in employee has one of roles, must run after RequireToken
+func (am *authMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !HasRole(ctx, roles...) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message" : "Forbidden", "details" : "requires role " + strings.Join(roles, " or ")})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func HasRole(ctx *gin.Context, roles ...string) bool {
+	for _, owned := range ctx.GetStringSlice(RolesKey) {
+		for _, role := range roles {
+			if owned == role {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+-- Normalize customer phone numbers to E.164 (+62 when there is no country code) and make them unique.
+-- Fails listing the numbers to fix by hand and the duplicate customers, nothing is changed then.
+-- For duplicates : deploy the new version, merge them with POST /customers/:id/merge, then run this again for the index.
+BEGIN;
+
+UPDATE customer SET phone_number = regexp_replace(phone_number, '[\s\-\.\(\)]', '', 'g');
+
+UPDATE customer SET phone_number = CASE
+    WHEN phone_number LIKE '+%' THEN phone_number
+    WHEN phone_number LIKE '00%' THEN '+' || substr(phone_number, 3)
+    WHEN phone_number LIKE '62%' THEN '+' || phone_number
+    WHEN phone_number LIKE '0%' THEN '+62' || substr(phone_number, 2)
+    WHEN phone_number LIKE '8%' THEN '+62' || phone_number
+    ELSE phone_number
+END;
+
+DO $$
+DECLARE
+    invalid TEXT;
+    duplicate TEXT;
+BEGIN
+    SELECT string_agg(customer_id || ' (' || phone_number || ')', ', ') INTO invalid
+    FROM customer WHERE phone_number !~ '^\+[1-9][0-9]{7,14}$';
+    IF invalid IS NOT NULL THEN
+        RAISE EXCEPTION 'customers with invalid phone numbers: %', invalid;
+    END IF;
+
+    SELECT string_agg(phone_number || ' (customers ' || ids || ')', ', ') INTO duplicate
+    FROM (SELECT phone_number, string_agg(customer_id::TEXT, ', ' ORDER BY customer_id) AS ids
+          FROM customer GROUP BY phone_number HAVING COUNT(*) > 1) AS d;
+    IF duplicate IS NOT NULL THEN
+        RAISE EXCEPTION 'duplicate customer phone numbers: %', duplicate;
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX idx_customer_phone_number ON customer(phone_number);
+
+COMMIT;
@@ -0,0 +1,33 @@
+-- Add the customer points ledger and keep the membership discount and redeemed points on every bill.
+-- Fully paid bills that are not cancelled earn their points, one point for every 1000 rupiah of the grand total.
+BEGIN;
+
+ALTER TABLE transaction
+    ADD COLUMN member_tier VARCHAR(20) NOT NULL DEFAULT 'member',
+    ADD COLUMN member_discount_percent INT NOT NULL DEFAULT 0,
+    ADD COLUMN member_discount INT NOT NULL DEFAULT 0,
+    ADD COLUMN points_redeemed INT NOT NULL DEFAULT 0 CHECK (points_redeemed >= 0),
+    ADD COLUMN points_discount INT NOT NULL DEFAULT 0;
+
+CREATE TABLE customer_point (
+    point_id SERIAL PRIMARY KEY,
+    customer_id INT NOT NULL,
+    transaction_id INT,
+    points INT NOT NULL,
+    entry_type VARCHAR(20) NOT NULL CHECK (entry_type IN ('earn', 'redeem', 'reverse', 'refund')),
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (customer_id) REFERENCES customer(customer_id),
+    FOREIGN KEY (transaction_id) REFERENCES transaction(transaction_id)
+);
+
+CREATE INDEX idx_customer_point_customer_id ON customer_point(customer_id);
+CREATE INDEX idx_customer_point_transaction_id ON customer_point(transaction_id);
+
+INSERT INTO customer_point (customer_id, transaction_id, points, entry_type, note)
+SELECT t.customer_id, t.transaction_id, t.total_bill / 1000, 'earn', 'bill paid'
+FROM transaction AS t
+WHERE t.status <> 'cancelled' AND t.total_bill >= 1000
+AND (SELECT COALESCE(SUM(p.amount), 0) FROM payment AS p WHERE p.transaction_id = t.transaction_id) >= t.total_bill;
+
+COMMIT;
@@ -0,0 +1,34 @@
+-- Add the prepaid customer wallet, its ledger and the wallet payment method.
+BEGIN;
+
+ALTER TABLE payment DROP CONSTRAINT payment_method_check;
+ALTER TABLE payment ADD CONSTRAINT payment_method_check CHECK (method IN ('cash', 'transfer', 'e_wallet', 'wallet'));
+
+CREATE TABLE customer_wallet (
+    customer_id INT PRIMARY KEY,
+    balance INT NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (customer_id) REFERENCES customer(customer_id)
+);
+
+CREATE TABLE wallet_entry (
+    wallet_entry_id SERIAL PRIMARY KEY,
+    customer_id INT NOT NULL,
+    transaction_id INT,
+    payment_id INT,
+    amount INT NOT NULL,
+    balance_after INT NOT NULL,
+    entry_type VARCHAR(20) NOT NULL CHECK (entry_type IN ('top_up', 'debit', 'refund')),
+    method VARCHAR(20) NOT NULL DEFAULT '',
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (customer_id) REFERENCES customer_wallet(customer_id),
+    FOREIGN KEY (transaction_id) REFERENCES transaction(transaction_id),
+    FOREIGN KEY (payment_id) REFERENCES payment(payment_id)
+);
+
+CREATE INDEX idx_wallet_entry_customer_id ON wallet_entry(customer_id);
+CREATE INDEX idx_wallet_entry_transaction_id ON wallet_entry(transaction_id);
+
+COMMIT;
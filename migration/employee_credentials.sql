@@ -0,0 +1,9 @@
+-- Add login credentials to employee.
+-- Existing employees can not log in until username and password are set with PUT /employees/:id.
+BEGIN;
+
+ALTER TABLE employee
+    ADD COLUMN username VARCHAR(50) UNIQUE,
+    ADD COLUMN password_hash VARCHAR(255);
+
+COMMIT;
@@ -0,0 +1,25 @@
+-- Add roles to employee.
+-- After running it give admin to at least one employee, ex :
+-- INSERT INTO employee_role (employee_id, role_id) SELECT 1, role_id FROM role WHERE name = 'admin';
+BEGIN;
+
+CREATE TABLE role (
+    role_id SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE
+);
+
+CREATE TABLE employee_role (
+    employee_id INT NOT NULL,
+    role_id INT NOT NULL,
+    PRIMARY KEY (employee_id, role_id),
+    FOREIGN KEY (employee_id) REFERENCES employee(employee_id) ON DELETE CASCADE,
+    FOREIGN KEY (role_id) REFERENCES role(role_id)
+);
+
+INSERT INTO role (name)
+VALUES
+('admin'),
+('cashier'),
+('washer');
+
+COMMIT;
@@ -0,0 +1,18 @@
+-- Add the payments of bills, run after migration/transaction_status.sql.
+-- Existing bills start unpaid, record what was already paid with POST /transactions/:id_bill/payments.
+BEGIN;
+
+CREATE TABLE payment (
+    payment_id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL,
+    amount INT NOT NULL CHECK (amount > 0),
+    method VARCHAR(20) NOT NULL CHECK (method IN ('cash', 'transfer', 'e_wallet')),
+    payment_type VARCHAR(20) NOT NULL DEFAULT 'payment' CHECK (payment_type IN ('deposit', 'payment')),
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    paid_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (transaction_id) REFERENCES transaction(transaction_id)
+);
+
+CREATE INDEX idx_payment_transaction_id ON payment(transaction_id);
+
+COMMIT;
@@ -0,0 +1,20 @@
+-- Add product categories, existing products stay without a category until one is assigned.
+BEGIN;
+
+CREATE TABLE category (
+    category_id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    sort_order INT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE product
+    ADD COLUMN category_id INT REFERENCES category(category_id),
+    ADD COLUMN description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN sort_order INT NOT NULL DEFAULT 0,
+    ADD COLUMN active BOOLEAN NOT NULL DEFAULT TRUE;
+
+COMMIT;
@@ -0,0 +1,37 @@
+-- Add packages as a product type, customer subscriptions to them and the quota each bill detail used.
+BEGIN;
+
+ALTER TABLE product
+    ADD COLUMN product_type VARCHAR(20) NOT NULL DEFAULT 'service' CHECK (product_type IN ('service', 'package')),
+    ADD COLUMN quota NUMERIC(10,2) NOT NULL DEFAULT 0;
+
+CREATE TABLE package_product (
+    package_id INT NOT NULL,
+    product_id INT NOT NULL,
+    PRIMARY KEY (package_id, product_id),
+    FOREIGN KEY (package_id) REFERENCES product(product_id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES product(product_id) ON DELETE CASCADE
+);
+
+CREATE TABLE customer_subscription (
+    subscription_id SERIAL PRIMARY KEY,
+    customer_id INT NOT NULL,
+    package_id INT NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    quota NUMERIC(10,2) NOT NULL,
+    used NUMERIC(10,2) NOT NULL DEFAULT 0 CHECK (used >= 0 AND used <= quota),
+    price INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date >= start_date),
+    FOREIGN KEY (customer_id) REFERENCES customer(customer_id),
+    FOREIGN KEY (package_id) REFERENCES product(product_id)
+);
+
+CREATE INDEX idx_customer_subscription_customer_id ON customer_subscription(customer_id);
+
+ALTER TABLE transaction_detail
+    ADD COLUMN subscription_id INT REFERENCES customer_subscription(subscription_id),
+    ADD COLUMN quota_used NUMERIC(10,2) NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,20 @@
+-- Add the product price history, the current price of every product is its first price.
+-- The price column of product is dropped, the history is the only source of prices.
+BEGIN;
+
+CREATE TABLE product_price (
+    product_price_id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL,
+    price INT NOT NULL CHECK (price >= 0),
+    effective_from DATE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (product_id, effective_from),
+    FOREIGN KEY (product_id) REFERENCES product(product_id) ON DELETE CASCADE
+);
+
+INSERT INTO product_price (product_id, price, effective_from)
+SELECT product_id, price, CURRENT_DATE FROM product;
+
+ALTER TABLE product DROP COLUMN price;
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- Add pricing models to product and decimal qty with a stored line total to transaction_detail.
+-- Existing products become per_piece so existing bills keep their total.
+BEGIN;
+
+ALTER TABLE product
+    ADD COLUMN pricing_model VARCHAR(20) NOT NULL DEFAULT 'per_piece' CHECK (pricing_model IN ('per_kg', 'per_piece', 'flat')),
+    ADD COLUMN min_weight NUMERIC(10,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE transaction_detail
+    ALTER COLUMN qty TYPE NUMERIC(10,2),
+    ADD COLUMN line_total INT;
+
+UPDATE transaction_detail SET line_total = product_price * qty;
+
+ALTER TABLE transaction_detail ALTER COLUMN line_total SET NOT NULL;
+
+COMMIT;
@@ -0,0 +1,22 @@
+-- Add the search indexes of the q param on customer, employee and product lists.
+-- pg_trgm and unaccent ship with PostgreSQL contrib, creating them needs a superuser or a trusted extension.
+BEGIN;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- Lower case without accents, immutable so the search indexes can use it
+CREATE FUNCTION search_normalize(value TEXT) RETURNS TEXT AS $$
+    SELECT lower(public.unaccent('public.unaccent', value))
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+CREATE INDEX idx_customer_search ON customer USING GIN (to_tsvector('simple', search_normalize(name || ' ' || COALESCE(address,''))));
+CREATE INDEX idx_customer_name_trgm ON customer USING GIN (search_normalize(name) gin_trgm_ops);
+CREATE INDEX idx_customer_phone_number_trgm ON customer USING GIN (phone_number gin_trgm_ops);
+CREATE INDEX idx_employee_search ON employee USING GIN (to_tsvector('simple', search_normalize(name || ' ' || COALESCE(address,'') || ' ' || COALESCE(username,''))));
+CREATE INDEX idx_employee_name_trgm ON employee USING GIN (search_normalize(name) gin_trgm_ops);
+CREATE INDEX idx_employee_phone_number_trgm ON employee USING GIN (phone_number gin_trgm_ops);
+CREATE INDEX idx_product_search ON product USING GIN (to_tsvector('simple', search_normalize(product_name)));
+CREATE INDEX idx_product_name_trgm ON product USING GIN (search_normalize(product_name) gin_trgm_ops);
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- Customers, employees and products are marked deleted instead of removed, so their bills stay.
+BEGIN;
+
+ALTER TABLE customer ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE employee ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE product ADD COLUMN deleted_at TIMESTAMP;
+
+-- A deleted customer gives its phone number free for a new customer
+DROP INDEX IF EXISTS idx_customer_phone_number;
+CREATE UNIQUE INDEX idx_customer_phone_number ON customer(phone_number) WHERE deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,45 @@
+-- Add the supply inventory, its bill of materials and stock ledger.
+-- Supplies start without stock, bills made before only take supplies when they are edited or change status.
+BEGIN;
+
+-- Consumables used by the services, stock is kept with every stock movement
+CREATE TABLE supply (
+    supply_id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    unit VARCHAR(20) NOT NULL,
+    stock NUMERIC(12,3) NOT NULL DEFAULT 0,
+    reorder_level NUMERIC(12,3) NOT NULL DEFAULT 0 CHECK (reorder_level >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Bill of materials, the supply used by one unit of the product qty
+CREATE TABLE product_supply (
+    product_id INT NOT NULL,
+    supply_id INT NOT NULL,
+    usage_per_unit NUMERIC(12,3) NOT NULL CHECK (usage_per_unit > 0),
+    PRIMARY KEY (product_id, supply_id),
+    FOREIGN KEY (product_id) REFERENCES product(product_id) ON DELETE CASCADE,
+    FOREIGN KEY (supply_id) REFERENCES supply(supply_id)
+);
+
+-- Stock ledger of a supply, taken by bills or entered by hand
+CREATE TABLE stock_movement (
+    stock_movement_id SERIAL PRIMARY KEY,
+    supply_id INT NOT NULL,
+    transaction_id INT,
+    employee_id INT,
+    change NUMERIC(12,3) NOT NULL,
+    stock_after NUMERIC(12,3) NOT NULL,
+    movement_type VARCHAR(20) NOT NULL CHECK (movement_type IN ('usage', 'return', 'restock', 'adjustment')),
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (supply_id) REFERENCES supply(supply_id),
+    FOREIGN KEY (transaction_id) REFERENCES transaction(transaction_id),
+    FOREIGN KEY (employee_id) REFERENCES employee(employee_id)
+);
+
+CREATE INDEX idx_stock_movement_supply_id ON stock_movement(supply_id);
+CREATE INDEX idx_stock_movement_transaction_id ON stock_movement(transaction_id);
+
+COMMIT;
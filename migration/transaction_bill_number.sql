@@ -0,0 +1,29 @@
+-- Add bill numbers to transaction and number the existing bills per day in id order.
+-- Run it with the BRANCH_CODE of this database, ex : psql -v branch_code=JKT -f migration/transaction_bill_number.sql
+BEGIN;
+
+CREATE TABLE bill_sequence (
+    branch_code VARCHAR(10) NOT NULL,
+    bill_day DATE NOT NULL,
+    last_number INT NOT NULL,
+    PRIMARY KEY (branch_code, bill_day)
+);
+
+ALTER TABLE transaction ADD COLUMN bill_number VARCHAR(30);
+
+UPDATE transaction AS t
+SET bill_number = 'EL-' || UPPER(:'branch_code') || '-' || TO_CHAR(n.bill_date, 'YYYYMMDD') || '-' || LPAD(n.number::TEXT, 4, '0')
+FROM (
+    SELECT transaction_id, bill_date, ROW_NUMBER() OVER (PARTITION BY bill_date ORDER BY transaction_id) AS number
+    FROM transaction
+) AS n
+WHERE t.transaction_id = n.transaction_id;
+
+INSERT INTO bill_sequence (branch_code, bill_day, last_number)
+SELECT UPPER(:'branch_code'), bill_date, COUNT(*) FROM transaction GROUP BY bill_date;
+
+ALTER TABLE transaction
+    ALTER COLUMN bill_number SET NOT NULL,
+    ADD CONSTRAINT transaction_bill_number_key UNIQUE (bill_number);
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- Add the cancel reason and time of cancelled bills and the void time of bill details
+-- removed by an edit, run after migration/payment.sql.
+BEGIN;
+
+ALTER TABLE transaction
+    ADD COLUMN cancel_reason VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN cancelled_at TIMESTAMP;
+
+ALTER TABLE transaction_detail ADD COLUMN removed_at TIMESTAMP;
+
+COMMIT;
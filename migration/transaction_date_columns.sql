@@ -0,0 +1,18 @@
+-- Convert the dd-mm-yyyy VARCHAR columns of transaction into real date types.
+-- Run once on databases created from the old DDL.sql.
+BEGIN;
+
+ALTER TABLE transaction
+    ALTER COLUMN bill_date TYPE DATE USING TO_DATE(bill_date, 'DD-MM-YYYY'),
+    ALTER COLUMN entry_date TYPE TIMESTAMPTZ USING TO_DATE(entry_date, 'DD-MM-YYYY')::TIMESTAMPTZ,
+    ALTER COLUMN finish_date TYPE TIMESTAMPTZ USING TO_DATE(finish_date, 'DD-MM-YYYY')::TIMESTAMPTZ;
+
+ALTER TABLE transaction
+    ALTER COLUMN bill_date SET NOT NULL,
+    ALTER COLUMN entry_date SET NOT NULL,
+    ALTER COLUMN finish_date SET NOT NULL;
+
+CREATE INDEX idx_transaction_entry_date ON transaction(entry_date);
+CREATE INDEX idx_transaction_finish_date ON transaction(finish_date);
+
+COMMIT;
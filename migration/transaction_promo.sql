@@ -0,0 +1,35 @@
+-- Add promo codes and keep the promo and discount of every bill.
+-- Existing bills get no discount so their total does not change.
+BEGIN;
+
+CREATE TABLE promo (
+    promo_id SERIAL PRIMARY KEY,
+    code VARCHAR(30) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    discount_type VARCHAR(20) NOT NULL CHECK (discount_type IN ('percent', 'fixed')),
+    discount_value INT NOT NULL CHECK (discount_value > 0),
+    max_discount INT NOT NULL DEFAULT 0,
+    min_spend INT NOT NULL DEFAULT 0,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    usage_limit INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Products a promo is restricted to, a promo without rows applies to every product
+CREATE TABLE promo_product (
+    promo_id INT NOT NULL,
+    product_id INT NOT NULL,
+    PRIMARY KEY (promo_id, product_id),
+    FOREIGN KEY (promo_id) REFERENCES promo(promo_id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES product(product_id) ON DELETE CASCADE
+);
+
+ALTER TABLE transaction
+    ADD COLUMN promo_id INT REFERENCES promo(promo_id),
+    ADD COLUMN discount INT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_transaction_promo_customer ON transaction(promo_id, customer_id);
+
+COMMIT;
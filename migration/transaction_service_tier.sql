@@ -0,0 +1,28 @@
+-- Add service tiers and keep the tier surcharge of every bill.
+-- Existing bills become regular without surcharge so their total does not change.
+BEGIN;
+
+CREATE TABLE service_tier (
+    service_tier_id SERIAL PRIMARY KEY,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    surcharge_type VARCHAR(20) NOT NULL CHECK (surcharge_type IN ('percent', 'fixed')),
+    surcharge_value INT NOT NULL DEFAULT 0 CHECK (surcharge_value >= 0),
+    turnaround_hours INT NOT NULL CHECK (turnaround_hours > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO service_tier (code, name, surcharge_type, surcharge_value, turnaround_hours)
+VALUES
+('regular', 'Regular', 'fixed', 0, 72),
+('express', 'Express', 'percent', 50, 24),
+('same_day', 'Same Day', 'percent', 100, 6);
+
+ALTER TABLE transaction
+    ADD COLUMN service_tier VARCHAR(20) NOT NULL DEFAULT 'regular',
+    ADD COLUMN surcharge_type VARCHAR(20) NOT NULL DEFAULT 'fixed' CHECK (surcharge_type IN ('percent', 'fixed')),
+    ADD COLUMN surcharge_value INT NOT NULL DEFAULT 0,
+    ADD COLUMN surcharge INT NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,23 @@
+-- Add the order status of transactions and its history.
+-- Existing bills start as received with one history row, update the ones already done by hand.
+BEGIN;
+
+ALTER TABLE transaction ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'received' CHECK (status IN ('received', 'washing', 'ready', 'picked_up', 'cancelled'));
+
+CREATE TABLE transaction_status_history (
+    history_id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (transaction_id) REFERENCES transaction(transaction_id)
+);
+
+INSERT INTO transaction_status_history (transaction_id, to_status, note)
+SELECT transaction_id, status, 'status added' FROM transaction;
+
+CREATE INDEX idx_transaction_status ON transaction(status);
+CREATE INDEX idx_transaction_status_history_transaction_id ON transaction_status_history(transaction_id);
+
+COMMIT;
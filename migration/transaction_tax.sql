@@ -0,0 +1,21 @@
+-- Add PPN with tax inclusive products and keep the subtotal, tax and grand total on every bill.
+-- Existing bills get no tax and their grand total is taken from their bill details.
+BEGIN;
+
+ALTER TABLE product
+    ADD COLUMN tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE transaction_detail
+    ADD COLUMN tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE transaction
+    ADD COLUMN subtotal INT NOT NULL DEFAULT 0,
+    ADD COLUMN tax_rate INT NOT NULL DEFAULT 0,
+    ADD COLUMN tax INT NOT NULL DEFAULT 0,
+    ADD COLUMN total_bill INT NOT NULL DEFAULT 0;
+
+UPDATE transaction AS t SET subtotal = COALESCE((SELECT SUM(td.line_total) FROM transaction_detail AS td WHERE td.transaction_id = t.transaction_id AND td.removed_at IS NULL), 0);
+
+UPDATE transaction SET total_bill = subtotal + surcharge - discount;
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- Merging a customer moves the wallet balance of the duplicate with a merge entry on both wallets.
+BEGIN;
+
+ALTER TABLE wallet_entry
+    DROP CONSTRAINT wallet_entry_entry_type_check,
+    ADD CONSTRAINT wallet_entry_entry_type_check CHECK (entry_type IN ('top_up', 'debit', 'refund', 'merge'));
+
+COMMIT;
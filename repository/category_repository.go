@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"submission-project-enigma-laundry/entity"
+	"github.com/lib/pq"
+)
+
+type CategoryRepository interface {
+	GetCategories() ([]entity.Category, error)
+	GetCategory(id int) (*entity.Category, error)
+	CreateCategory(category *entity.Category) (*entity.Category, error)
+	UpdateCategory(id int, category *entity.Category) (*entity.Category, error)
+	GetCatalogue() ([]entity.Category, error)
+}
+
+// Returned when no category has the requested id
+var ErrCategoryNotFound = errors.New("category not found")
+
+// Returned when the name already belongs to another category
+var ErrCategoryNameTaken = errors.New("category name is already taken")
+
+// Name of the catalogue group of the active products without a category
+const uncategorizedName = "Other"
+
+type categoryRepository struct {
+	DB *sql.DB
+}
+
+func NewCategoryRepo(db *sql.DB) CategoryRepository {
+	return &categoryRepository{DB: db}
+}
+
+func (cr *categoryRepository) GetCategories() ([]entity.Category, error) {
+	categories := []entity.Category{}
+
+	query := "SELECT category_id,name,description,sort_order,active FROM category ORDER BY sort_order,name"
+
+	rows, err := cr.DB.Query(query)
+	if err != nil {
+		return categories, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		category := entity.Category{}
+		err = rows.Scan(&category.Category_id, &category.Name, &category.Description, &category.Sort_order, &category.Active)
+		if err != nil {
+			return categories, err
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, rows.Err()
+}
+
+func (cr *categoryRepository) GetCategory(id int) (*entity.Category, error) {
+	category := entity.Category{}
+
+	query := "SELECT category_id,name,description,sort_order,active FROM category WHERE category_id = $1"
+
+	err := cr.DB.QueryRow(query, id).Scan(&category.Category_id, &category.Name, &category.Description, &category.Sort_order, &category.Active)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &category, ErrCategoryNotFound
+		}
+		return &category, err
+	}
+
+	return &category, nil
+}
+
+func (cr *categoryRepository) CreateCategory(category *entity.Category) (*entity.Category, error) {
+	query := "INSERT INTO category (name,description,sort_order,active) VALUES ($1,$2,$3,$4) RETURNING category_id"
+
+	err := cr.DB.QueryRow(query, category.Name, category.Description, category.Sort_order, category.Active).Scan(&category.Category_id)
+	if err != nil {
+		return category, categoryError(err)
+	}
+	return category, nil
+}
+
+func (cr *categoryRepository) UpdateCategory(id int, category *entity.Category) (*entity.Category, error) {
+	query := "UPDATE category SET name = $2,description = $3,sort_order = $4,active = $5,updated_at = CURRENT_TIMESTAMP WHERE category_id = $1"
+
+	result, err := cr.DB.Exec(query, id, category.Name, category.Description, category.Sort_order, category.Active)
+	if err != nil {
+		return category, categoryError(err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return category, err
+	}
+	if updated == 0 {
+		return category, ErrCategoryNotFound
+	}
+	return category, nil
+}
+
+// Active categories with their active products, both by sort order. Active products
+// without a category come last in their own group, categories without products are left out
+func (cr *categoryRepository) GetCatalogue() ([]entity.Category, error) {
+	catalogue := []entity.Category{}
+
+	query := `SELECT COALESCE(c.category_id::text,''),COALESCE(c.name,$1),COALESCE(c.description,''),COALESCE(c.sort_order,0),
+	p.product_id,p.product_name,` + priceOnQuery("p", "CURRENT_DATE") + `,p.unit,p.pricing_model,p.min_weight,p.tax_inclusive,p.product_type,p.quota,
+	ARRAY(SELECT pp.product_id::text FROM package_product AS pp WHERE pp.package_id = p.product_id ORDER BY pp.product_id),p.description,p.sort_order
+	FROM product AS p
+	LEFT JOIN category AS c ON p.category_id = c.category_id
+	WHERE p.active AND p.deleted_at IS NULL AND (c.category_id IS NULL OR c.active)
+	ORDER BY c.category_id IS NULL,c.sort_order,c.name,p.sort_order,p.product_name,p.product_id`
+
+	rows, err := cr.DB.Query(query, uncategorizedName)
+	if err != nil {
+		return catalogue, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		category := entity.Category{Active: true}
+		product := entity.Product{Active: true}
+		err = rows.Scan(&category.Category_id, &category.Name, &category.Description, &category.Sort_order,
+			&product.Product_id, &product.Product_name, &product.Price, &product.Unit, &product.Pricing_model, &product.Min_weight, &product.Tax_inclusive, &product.Product_type, &product.Quota,
+			pq.Array(&product.Covered_product_ids), &product.Description, &product.Sort_order)
+		if err != nil {
+			return catalogue, err
+		}
+		product.Category_id = category.Category_id
+
+		// Rows come grouped by category, a new group starts when the category changes
+		last := len(catalogue) - 1
+		if last < 0 || catalogue[last].Category_id != category.Category_id {
+			catalogue = append(catalogue, category)
+			last++
+		}
+		catalogue[last].Products = append(catalogue[last].Products, product)
+	}
+
+	return catalogue, rows.Err()
+}
+
+func categoryError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrCategoryNameTaken
+	}
+	return err
+}
@@ -1,122 +1,302 @@
-package repository
-
-import (
-	"database/sql"
-	"errors"
-	"submission-project-enigma-laundry/entity"
-	_ "github.com/lib/pq"
-)
-
-type CustomerRepository interface {
-	GetCustomer() (*sql.Rows, error)
-	GetDetailCustomer(id int,customer *entity.Customer) (*entity.Customer,error)
-	IsCustomerExist(id int,customer *entity.Customer) (bool,error)
-	CustomerInTransaction(customerId int,transaction *entity.Transaction)  (bool,error)
-	CreateCustomer(customer *entity.Customer) (*entity.Customer, error)	
-	UpdateCustomer(id int,customer *entity.Customer) (*entity.Customer,error)
-	DeleteCustomer(id int) (bool,error)
-}
-
-type customerRepository struct {
-	DB *sql.DB
-}
-
-func NewCustomerRepo(db *sql.DB) CustomerRepository {
-	return &customerRepository{DB: db}
-}
-
-func (cr *customerRepository) IsCustomerExist(id int, customer *entity.Customer) (bool, error) {
-	query := "SELECT customer_id FROM customer WHERE customer_id = $1"
-	
-	// Execute the query and scan the result
-	err := cr.DB.QueryRow(query, id).Scan(&customer.Customer_id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			// No customer found
-			return false, nil // No error, just return false
-		}
-		// Return any other errors encountered
-		return false, err
-	}
-
-	// Customer exists
-	return true, nil
-}
-
-func (cr *customerRepository) CustomerInTransaction(customerId int,transaction *entity.Transaction)  (bool,error) {
-	query := "SELECT customer_id FROM transaction WHERE customer_id = $1"
-
-	err := cr.DB.QueryRow(query,customerId).Scan(&transaction.Customer_id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			// No customer found
-			return false, nil // No error, just return false
-		}
-		// Return any other errors encountered
-		return false, err
-	}
-
-	// Customer exists
-	return true, nil
-}
-
-
-func (cr *customerRepository) CreateCustomer(customer *entity.Customer) (*entity.Customer, error) {
-	// insert customer data into db
-	insert_query := "INSERT INTO customer (name,phone_number,address) VALUES ($1, $2, $3) RETURNING customer_id;"
-
-	err := cr.DB.QueryRow(insert_query, customer.Name, customer.Phone_number, customer.Address).Scan(&customer.Customer_id)
-	if err != nil {
-		return customer, err // Handle error if the query fails
-	}
-	return customer, nil
-}
-
-func (cr *customerRepository) GetCustomer() (*sql.Rows, error) {
-	// Get all data from customer table
-	select_all := "SELECT customer_id,name,phone_number,address FROM customer;"
-
-	rows,err := cr.DB.Query(select_all)
-	if err != nil {
-		return rows,err
-	}
-	return rows,nil
-}
-
-func (cr *customerRepository) GetDetailCustomer(id int,customer *entity.Customer) (*entity.Customer,error) {
-	select_by_id := "SELECT customer_id,name,phone_number,address FROM customer WHERE customer_id = $1"
-	
-	err := cr.DB.QueryRow(select_by_id,id).Scan(&customer.Customer_id,&customer.Name,&customer.Phone_number,&customer.Address)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			err = errors.New("customer not found")
-			return customer , err
-		}
-
-		return customer , err
-	}
-
-	return customer , nil
-}
-
-func (cr *customerRepository) UpdateCustomer(id int,customer *entity.Customer) (*entity.Customer,error) {
-	update := "UPDATE customer SET name = $2,phone_number = $3,address = $4 WHERE customer_id = $1"
-	_, err := cr.DB.Exec(update,id,customer.Name,customer.Phone_number,customer.Address)
-	if err != nil {
-		return customer,err
-	}
-	return customer,nil
-}
-
-func (cr *customerRepository) DeleteCustomer(id int) (bool,error) {
-	query := "DELETE FROM customer WHERE customer_id = $1"
-	// Execute the query and scan the result
-	_,err := cr.DB.Exec(query,id)
-	if err != nil {
-		// Return any other errors encountered
-		return false, err
-	}
-
-	// Customer exists
-	return true, nil
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"submission-project-enigma-laundry/entity"
+	"github.com/lib/pq"
+)
+
+type CustomerRepository interface {
+	GetCustomer(filter ListFilter, page entity.Page_request) (*sql.Rows, int, error)
+	GetDetailCustomer(id int,customer *entity.Customer) (*entity.Customer,error)
+	IsCustomerExist(id int,customer *entity.Customer) (bool,error)
+	CreateCustomer(customer *entity.Customer) (*entity.Customer, error)	
+	UpdateCustomer(id int,customer *entity.Customer) (*entity.Customer,error)
+	DeleteCustomer(id int) (bool,error)
+	RestoreCustomer(id int) (bool,error)
+	GetCustomerSummary(id int) (*entity.Customer_summary,error)
+	GetCustomerByPhoneNumber(phoneNumber string) (*entity.Customer,bool,error)
+	MergeCustomer(id int,duplicateId int) (int,error)
+}
+
+// Returned when another customer already has the phone number
+var ErrPhoneNumberTaken = errors.New("phone number is already registered")
+
+// Returned when a customer to merge does not exist
+var ErrCustomerNotFound = errors.New("customer not found")
+
+// Number of favourite products in the customer summary
+const favouriteProductsLimit = 3
+
+type customerRepository struct {
+	DB *sql.DB
+}
+
+func NewCustomerRepo(db *sql.DB) CustomerRepository {
+	return &customerRepository{DB: db}
+}
+
+func (cr *customerRepository) IsCustomerExist(id int, customer *entity.Customer) (bool, error) {
+	// Deleted customers can not be used anymore
+	query := "SELECT customer_id FROM customer WHERE customer_id = $1 AND deleted_at IS NULL"
+	
+	// Execute the query and scan the result
+	err := cr.DB.QueryRow(query, id).Scan(&customer.Customer_id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// No customer found
+			return false, nil // No error, just return false
+		}
+		// Return any other errors encountered
+		return false, err
+	}
+
+	// Customer exists
+	return true, nil
+}
+
+func (cr *customerRepository) CreateCustomer(customer *entity.Customer) (*entity.Customer, error) {
+	// insert customer data into db
+	insert_query := "INSERT INTO customer (name,phone_number,address) VALUES ($1, $2, $3) RETURNING customer_id;"
+
+	err := cr.DB.QueryRow(insert_query, customer.Name, customer.Phone_number, customer.Address).Scan(&customer.Customer_id)
+	if err != nil {
+		return customer, customerError(err) // Handle error if the query fails
+	}
+	return customer, nil
+}
+
+func (cr *customerRepository) GetCustomer(filter ListFilter, page entity.Page_request) (*sql.Rows, int, error) {
+	qb := queryBuilder{}
+	customerSearchColumns.filter(filter, page, &qb)
+
+	total := 0
+	err := cr.DB.QueryRow("SELECT COUNT(*) FROM customer"+qb.clause(), qb.args...).Scan(&total)
+	if err != nil {
+		return nil, total, err
+	}
+
+	pageQuery, err := buildPageQuery(page, customerSortColumns, "customer_id", &qb)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Get one page of data from customer table
+	select_all := "SELECT customer_id,name,phone_number,address,deleted_at FROM customer" + qb.clause() + pageQuery
+
+	rows,err := cr.DB.Query(select_all, qb.args...)
+	if err != nil {
+		return rows,total,err
+	}
+	return rows,total,nil
+}
+
+func (cr *customerRepository) GetDetailCustomer(id int,customer *entity.Customer) (*entity.Customer,error) {
+	select_by_id := "SELECT customer_id,name,phone_number,address,deleted_at FROM customer WHERE customer_id = $1"
+	
+	err := cr.DB.QueryRow(select_by_id,id).Scan(&customer.Customer_id,&customer.Name,&customer.Phone_number,&customer.Address,&customer.Deleted_at)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = errors.New("customer not found")
+			return customer , err
+		}
+
+		return customer , err
+	}
+
+	return customer , nil
+}
+
+func (cr *customerRepository) UpdateCustomer(id int,customer *entity.Customer) (*entity.Customer,error) {
+	update := "UPDATE customer SET name = $2,phone_number = $3,address = $4 WHERE customer_id = $1"
+	_, err := cr.DB.Exec(update,id,customer.Name,customer.Phone_number,customer.Address)
+	if err != nil {
+		return customer,customerError(err)
+	}
+	return customer,nil
+}
+
+// Customer is only marked deleted so its bills, points and wallet stay
+func (cr *customerRepository) DeleteCustomer(id int) (bool,error) {
+	query := "UPDATE customer SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE customer_id = $1 AND deleted_at IS NULL"
+	// Execute the query and scan the result
+	_,err := cr.DB.Exec(query,id)
+	if err != nil {
+		// Return any other errors encountered
+		return false, err
+	}
+
+	// Customer exists
+	return true, nil
+}
+
+// Returns false when the customer does not exist
+func (cr *customerRepository) RestoreCustomer(id int) (bool,error) {
+	result, err := cr.DB.Exec("UPDATE customer SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE customer_id = $1", id)
+	if err != nil {
+		return false, customerError(err)
+	}
+	restored, err := result.RowsAffected()
+	return restored == 1, err
+}
+
+// Visits, spend and favourite products are counted in SQL over the bills that are not cancelled
+func (cr *customerRepository) GetCustomerSummary(id int) (*entity.Customer_summary,error) {
+	summary := entity.Customer_summary{Customer_id: strconv.Itoa(id), Favourite_products: []entity.Favourite_product{}}
+
+	var firstVisit, lastVisit sql.NullTime
+	query := `SELECT COUNT(*),COALESCE(SUM(t.total_bill),0),COALESCE(ROUND(AVG(t.total_bill)),0)::int,
+	COALESCE(SUM(t.total_bill - ` + paidAmountQuery + `),0),MIN(t.entry_date),MAX(t.entry_date)
+	FROM transaction AS t WHERE t.customer_id = $1 AND t.status <> $2`
+	err := cr.DB.QueryRow(query, id, entity.Status_cancelled).Scan(&summary.Visit_count, &summary.Lifetime_spend, &summary.Average_bill, &summary.Outstanding, &firstVisit, &lastVisit)
+	if err != nil {
+		return &summary, err
+	}
+	if firstVisit.Valid {
+		summary.First_visit = &firstVisit.Time
+	}
+	if lastVisit.Valid {
+		summary.Last_visit = &lastVisit.Time
+	}
+
+	favourites := `SELECT p.product_id,p.product_name,COUNT(DISTINCT td.transaction_id),SUM(td.qty)
+	FROM transaction_detail AS td
+	INNER JOIN transaction AS t ON td.transaction_id = t.transaction_id
+	INNER JOIN product AS p ON td.product_id = p.product_id
+	WHERE t.customer_id = $1 AND t.status <> $2 AND td.removed_at IS NULL
+	GROUP BY p.product_id,p.product_name
+	ORDER BY COUNT(DISTINCT td.transaction_id) DESC,SUM(td.qty) DESC,p.product_id
+	LIMIT $3`
+
+	rows, err := cr.DB.Query(favourites, id, entity.Status_cancelled, favouriteProductsLimit)
+	if err != nil {
+		return &summary, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		product := entity.Favourite_product{}
+		err = rows.Scan(&product.Product_id, &product.Product_name, &product.Bill_count, &product.Qty)
+		if err != nil {
+			return &summary, err
+		}
+		summary.Favourite_products = append(summary.Favourite_products, product)
+	}
+
+	return &summary, rows.Err()
+}
+
+func (cr *customerRepository) GetCustomerByPhoneNumber(phoneNumber string) (*entity.Customer,bool,error) {
+	customer := entity.Customer{}
+
+	// Deleted customers give their phone number free, like the unique index
+	query := "SELECT customer_id,name,phone_number,address,deleted_at FROM customer WHERE phone_number = $1 AND deleted_at IS NULL"
+	err := cr.DB.QueryRow(query, phoneNumber).Scan(&customer.Customer_id, &customer.Name, &customer.Phone_number, &customer.Address, &customer.Deleted_at)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &customer, false, nil
+		}
+		return &customer, false, err
+	}
+
+	return &customer, true, nil
+}
+
+// Move the bills, points, wallet and subscriptions of the duplicate to the customer and mark the duplicate
+// deleted, returns the number of bills moved. Both customers must not be deleted
+func (cr *customerRepository) MergeCustomer(id int,duplicateId int) (int,error) {
+	tx, err := cr.DB.Begin()
+	if err != nil {
+		err = fmt.Errorf("failed starting transaction , %s", err)
+		return 0, err
+	}
+
+	// Lock both customers in id order so bills, points and wallet of either wait for the merge
+	locked := 0
+	err = tx.QueryRow("SELECT COUNT(*) FROM (SELECT customer_id FROM customer WHERE customer_id IN ($1,$2) AND deleted_at IS NULL ORDER BY customer_id FOR UPDATE) AS c", id, duplicateId).Scan(&locked)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if locked != 2 {
+		tx.Rollback()
+		return 0, fmt.Errorf("%w, id %d or %d is deleted or does not exist", ErrCustomerNotFound, id, duplicateId)
+	}
+
+	result, err := tx.Exec("UPDATE transaction SET customer_id = $1, updated_at = CURRENT_TIMESTAMP WHERE customer_id = $2", id, duplicateId)
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("failed update transaction , %s", err)
+	}
+	moved, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	for _, query := range []string{
+		"UPDATE customer_point SET customer_id = $1 WHERE customer_id = $2",
+		"UPDATE customer_subscription SET customer_id = $1 WHERE customer_id = $2",
+	} {
+		_, err = tx.Exec(query, id, duplicateId)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	err = mergeWallet(tx, id, duplicateId)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	// The duplicate stays as deleted so it is known who was merged
+	_, err = tx.Exec("UPDATE customer SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE customer_id = $1", duplicateId)
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("failed delete customer , %s", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		err = fmt.Errorf("failed commit transaction , %s", err)
+		return 0, err
+	}
+
+	return int(moved), nil
+}
+
+// Balance of the duplicate wallet moves to the customer wallet with a merge entry on both,
+// so the balanceAfter of either ledger keeps adding up. The ledger of the duplicate stays with it
+func mergeWallet(tx *sql.Tx, id int, duplicateId int) error {
+	balance := 0
+	err := tx.QueryRow("SELECT balance FROM customer_wallet WHERE customer_id = $1 FOR UPDATE", duplicateId).Scan(&balance)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+	if balance == 0 {
+		return nil
+	}
+
+	err = insertWalletEntry(tx, &entity.Wallet_entry{Customer_id: strconv.Itoa(duplicateId), Amount: -balance, Entry_type: entity.Wallet_merge, Note: fmt.Sprintf("merged into customer %d", id)})
+	if err != nil {
+		return err
+	}
+	return insertWalletEntry(tx, &entity.Wallet_entry{Customer_id: strconv.Itoa(id), Amount: balance, Entry_type: entity.Wallet_merge, Note: fmt.Sprintf("merged from customer %d", duplicateId)})
+}
+
+func customerError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrPhoneNumberTaken
+	}
+	return err
 }
\ No newline at end of file
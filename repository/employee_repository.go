@@ -1,122 +1,262 @@
-package repository
-
-import (
-	"database/sql"
-	"errors"
-	"submission-project-enigma-laundry/entity"
-	_ "github.com/lib/pq"
-)
-
-type EmployeeRepository interface {
-	GetEmployee() (*sql.Rows, error)
-	GetDetailEmployee(id int,Employee *entity.Employee) (*entity.Employee,error)
-	IsEmployeeExist(id int,Employee *entity.Employee) (bool,error)
-	EmployeeInTransaction(id int,transaction *entity.Transaction)  (bool,error)
-	CreateEmployee(Employee *entity.Employee) (*entity.Employee, error)	
-	UpdateEmployee(id int,Employee *entity.Employee) (*entity.Employee,error)
-	DeleteEmployee(id int) (bool,error)
-}
-
-type employeeRepository struct {
-	DB *sql.DB
-}
-
-func NewEmployeeRepo(db *sql.DB) EmployeeRepository {
-	return &employeeRepository{DB: db}
-}
-
-func (er *employeeRepository) IsEmployeeExist(id int, employee *entity.Employee) (bool, error) {
-	query := "SELECT employee_id FROM employee WHERE employee_id = $1"
-	
-	// Execute the query and scan the result
-	err := er.DB.QueryRow(query, id).Scan(&employee.Employee_id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			// No employee found
-			return false, nil // No error, just return false
-		}
-		// Return any other errors encountered
-		return false, err
-	}
-
-	// Employee exists
-	return true, nil
-}
-
-func (er *employeeRepository) EmployeeInTransaction(id int,transaction *entity.Transaction)  (bool,error) {
-	query := "SELECT employee_id FROM transaction WHERE employee_id = $1"
-
-	err := er.DB.QueryRow(query,id).Scan(&transaction.Employee_id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			// No employee found
-			return false, nil // No error, just return false
-		}
-		// Return any other errors encountered
-		return false, err
-	}
-
-	// employee exists
-	return true, nil
-}
-
-
-func (er *employeeRepository) CreateEmployee(employee *entity.Employee) (*entity.Employee, error) {
-	// insert employee data into db
-	insert_query := "INSERT INTO employee (name,phone_number,address) VALUES ($1, $2, $3) RETURNING employee_id;"
-
-	err := er.DB.QueryRow(insert_query, employee.Name,employee.Phone_number,employee.Address).Scan(&employee.Employee_id)
-	if err != nil {
-		return employee, err // Handle error if the query fails
-	}
-	return employee, nil
-}
-
-func (er *employeeRepository) GetEmployee() (*sql.Rows, error) {
-	// Get all data from customer table
-	select_all := "SELECT employee_id,name,phone_number,address FROM employee;"
-
-	rows,err := er.DB.Query(select_all)
-	if err != nil {
-		return rows,err
-	}
-	return rows,nil
-}
-
-func (er *employeeRepository) GetDetailEmployee(id int,employee *entity.Employee) (*entity.Employee,error) {
-	select_by_id := "SELECT employee_id,name,phone_number,address FROM employee WHERE employee_id = $1"
-	
-	err := er.DB.QueryRow(select_by_id,id).Scan(&employee.Employee_id,&employee.Name,&employee.Phone_number,&employee.Address)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			err = errors.New("employee not found")
-			return employee , err
-		}
-
-		return employee , err
-	}
-
-	return employee , nil
-}
-
-func (er *employeeRepository) UpdateEmployee(id int,employee *entity.Employee) (*entity.Employee,error) {
-	update := "UPDATE employee SET name = $2,phone_number = $3,address = $4 WHERE employee_id = $1"
-
-	_, err := er.DB.Exec(update,id,employee.Name,employee.Phone_number,employee.Address)
-	if err != nil {
-		return employee,err
-	}
-	return employee,nil
-}
-
-func (er *employeeRepository) DeleteEmployee(id int) (bool,error) {
-	query := "DELETE FROM employee WHERE employee_id = $1"
-	// Execute the query and scan the result
-	_,err := er.DB.Exec(query,id)
-	if err != nil {
-		// Return any other errors encountered
-		return false, err
-	}
-
-	return true, nil
-}
\ No newline at end of file
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"submission-project-enigma-laundry/entity"
+	"github.com/lib/pq"
+)
+
+type EmployeeRepository interface {
+	GetEmployee(filter ListFilter, page entity.Page_request) (*sql.Rows, int, error)
+	GetDetailEmployee(id int,Employee *entity.Employee) (*entity.Employee,error)
+	GetEmployeeByUsername(username string,Employee *entity.Employee) (*entity.Employee,error)
+	IsEmployeeExist(id int,Employee *entity.Employee) (bool,error)
+	CreateEmployee(Employee *entity.Employee) (*entity.Employee, error)	
+	UpdateEmployee(id int,Employee *entity.Employee) (*entity.Employee,error)
+	DeleteEmployee(id int) (bool,error)
+	RestoreEmployee(id int) (bool,error)
+	AssignRole(id int,role string) error
+	RevokeRole(id int,role string) error
+}
+
+// Returned when the username already belongs to another employee
+var ErrUsernameTaken = errors.New("username is already taken")
+
+// Returned when revoking admin from or deleting the only admin left
+var ErrLastAdmin = errors.New("can not remove the last admin")
+
+// Other admins that are not deleted
+const otherAdminsQuery = `SELECT COUNT(*) FROM employee_role AS er
+	INNER JOIN role AS r ON er.role_id = r.role_id
+	INNER JOIN employee AS e ON er.employee_id = e.employee_id
+	WHERE r.name = $1 AND er.employee_id <> $2 AND e.deleted_at IS NULL`
+
+// Role names of employee e
+const employeeRolesQuery = `ARRAY(SELECT r.name FROM employee_role AS er INNER JOIN role AS r ON er.role_id = r.role_id WHERE er.employee_id = e.employee_id ORDER BY r.name)`
+
+type employeeRepository struct {
+	DB *sql.DB
+}
+
+func NewEmployeeRepo(db *sql.DB) EmployeeRepository {
+	return &employeeRepository{DB: db}
+}
+
+func (er *employeeRepository) IsEmployeeExist(id int, employee *entity.Employee) (bool, error) {
+	// Deleted employees can not be used anymore
+	query := "SELECT employee_id FROM employee WHERE employee_id = $1 AND deleted_at IS NULL"
+	
+	// Execute the query and scan the result
+	err := er.DB.QueryRow(query, id).Scan(&employee.Employee_id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// No employee found
+			return false, nil // No error, just return false
+		}
+		// Return any other errors encountered
+		return false, err
+	}
+
+	// Employee exists
+	return true, nil
+}
+
+func (er *employeeRepository) CreateEmployee(employee *entity.Employee) (*entity.Employee, error) {
+	// insert employee data into db
+	insert_query := "INSERT INTO employee (name,phone_number,address,username,password_hash) VALUES ($1, $2, $3, $4, $5) RETURNING employee_id;"
+
+	err := er.DB.QueryRow(insert_query, employee.Name,employee.Phone_number,employee.Address,employee.Username,employee.Password_hash).Scan(&employee.Employee_id)
+	if err != nil {
+		return employee, usernameError(err) // Handle error if the query fails
+	}
+	return employee, nil
+}
+
+func (er *employeeRepository) GetEmployee(filter ListFilter, page entity.Page_request) (*sql.Rows, int, error) {
+	qb := queryBuilder{}
+	employeeSearchColumns.filter(filter, page, &qb)
+
+	total := 0
+	err := er.DB.QueryRow("SELECT COUNT(*) FROM employee AS e"+qb.clause(), qb.args...).Scan(&total)
+	if err != nil {
+		return nil, total, err
+	}
+
+	pageQuery, err := buildPageQuery(page, employeeSortColumns, "employee_id", &qb)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Get one page of data from employee table
+	select_all := "SELECT e.employee_id,e.name,e.phone_number,e.address,COALESCE(e.username,'')," + employeeRolesQuery + ",e.deleted_at FROM employee AS e" + qb.clause() + pageQuery
+
+	rows,err := er.DB.Query(select_all, qb.args...)
+	if err != nil {
+		return rows,total,err
+	}
+	return rows,total,nil
+}
+
+func (er *employeeRepository) GetDetailEmployee(id int,employee *entity.Employee) (*entity.Employee,error) {
+	select_by_id := "SELECT e.employee_id,e.name,e.phone_number,e.address,COALESCE(e.username,'')," + employeeRolesQuery + ",e.deleted_at FROM employee AS e WHERE e.employee_id = $1"
+	
+	err := er.DB.QueryRow(select_by_id,id).Scan(&employee.Employee_id,&employee.Name,&employee.Phone_number,&employee.Address,&employee.Username,pq.Array(&employee.Roles),&employee.Deleted_at)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = errors.New("employee not found")
+			return employee , err
+		}
+
+		return employee , err
+	}
+
+	return employee , nil
+}
+
+// Employees without credentials or deleted can not log in, only match the ones that can
+func (er *employeeRepository) GetEmployeeByUsername(username string,employee *entity.Employee) (*entity.Employee,error) {
+	select_by_username := "SELECT e.employee_id,e.name,e.phone_number,e.address,e.username,e.password_hash," + employeeRolesQuery + " FROM employee AS e WHERE e.username = $1 AND e.password_hash IS NOT NULL AND e.deleted_at IS NULL"
+
+	err := er.DB.QueryRow(select_by_username,username).Scan(&employee.Employee_id,&employee.Name,&employee.Phone_number,&employee.Address,&employee.Username,&employee.Password_hash,pq.Array(&employee.Roles))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = errors.New("employee not found")
+			return employee , err
+		}
+
+		return employee , err
+	}
+
+	return employee , nil
+}
+
+// Password hash is kept when employee has no new one
+func (er *employeeRepository) UpdateEmployee(id int,employee *entity.Employee) (*entity.Employee,error) {
+	update := "UPDATE employee SET name = $2,phone_number = $3,address = $4,username = NULLIF($5,''),password_hash = COALESCE(NULLIF($6,''),password_hash) WHERE employee_id = $1"
+
+	_, err := er.DB.Exec(update,id,employee.Name,employee.Phone_number,employee.Address,employee.Username,employee.Password_hash)
+	if err != nil {
+		return employee,usernameError(err)
+	}
+	return employee,nil
+}
+
+// Employee is only marked deleted so the bills it handled stay, the last admin can not be deleted
+func (er *employeeRepository) DeleteEmployee(id int) (bool,error) {
+	tx, err := er.DB.Begin()
+	if err != nil {
+		err = fmt.Errorf("failed starting transaction , %s", err)
+		return false, err
+	}
+
+	// Same lock as RevokeRole so the last two admins can not delete each other at the same time
+	_,err = tx.Exec("LOCK TABLE employee_role IN SHARE ROW EXCLUSIVE MODE")
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	isAdmin := false
+	err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM employee_role AS er INNER JOIN role AS r ON er.role_id = r.role_id WHERE r.name = $1 AND er.employee_id = $2)",entity.Role_admin,id).Scan(&isAdmin)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if isAdmin {
+		admins := 0
+		err = tx.QueryRow(otherAdminsQuery,entity.Role_admin,id).Scan(&admins)
+		if err != nil {
+			tx.Rollback()
+			return false, err
+		}
+		if admins == 0 {
+			tx.Rollback()
+			return false, ErrLastAdmin
+		}
+	}
+
+	_,err = tx.Exec("UPDATE employee SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE employee_id = $1 AND deleted_at IS NULL",id)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		err = fmt.Errorf("failed commit transaction , %s", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// Returns false when the employee does not exist
+func (er *employeeRepository) RestoreEmployee(id int) (bool,error) {
+	result, err := er.DB.Exec("UPDATE employee SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE employee_id = $1", id)
+	if err != nil {
+		return false, err
+	}
+	restored, err := result.RowsAffected()
+	return restored == 1, err
+}
+
+func (er *employeeRepository) AssignRole(id int,role string) error {
+	query := "INSERT INTO employee_role (employee_id,role_id) SELECT $1,role_id FROM role WHERE name = $2 ON CONFLICT DO NOTHING"
+
+	_,err := er.DB.Exec(query,id,role)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (er *employeeRepository) RevokeRole(id int,role string) error {
+	tx, err := er.DB.Begin()
+	if err != nil {
+		err = fmt.Errorf("failed starting transaction , %s", err)
+		return err
+	}
+
+	// Block concurrent revokes so two admins can not remove each other at the same time
+	_,err = tx.Exec("LOCK TABLE employee_role IN SHARE ROW EXCLUSIVE MODE")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if role == entity.Role_admin {
+		admins := 0
+		err = tx.QueryRow(otherAdminsQuery,entity.Role_admin,id).Scan(&admins)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if admins == 0 {
+			tx.Rollback()
+			return ErrLastAdmin
+		}
+	}
+
+	query := "DELETE FROM employee_role WHERE employee_id = $1 AND role_id = (SELECT role_id FROM role WHERE name = $2)"
+	_,err = tx.Exec(query,id,role)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		err = fmt.Errorf("failed commit transaction , %s", err)
+		return err
+	}
+	return nil
+}
+
+func usernameError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrUsernameTaken
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"submission-project-enigma-laundry/entity"
+)
+
+// Returned when the sort field or cursor of a list request can not be used
+var ErrInvalidPaging = errors.New("invalid paging")
+
+// Sortable fields of every list, field name in the API mapped to its SQL column
+var customerSortColumns = map[string]string{
+	"id":          "customer_id",
+	"name":        "name",
+	"phoneNumber": "phone_number",
+	"address":     "address",
+}
+
+var employeeSortColumns = map[string]string{
+	"id":          "employee_id",
+	"name":        "name",
+	"phoneNumber": "phone_number",
+	"address":     "address",
+}
+
+var productSortColumns = map[string]string{
+	"id":        "product_id",
+	"name":      "product_name",
+	"price":     productPriceQuery,
+	"unit":      "unit",
+	"sortOrder": "sort_order",
+}
+
+var transactionSortColumns = map[string]string{
+	"id":           "t.transaction_id",
+	"billDate":     "t.bill_date",
+	"entryDate":    "t.entry_date",
+	"finishDate":   "t.finish_date",
+	"status":       "t.status",
+	"customerName": "c.name",
+	"totalBill":    totalBillQuery,
+}
+
+func EncodeCursor(lastId string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(lastId))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("%w, cursor is malformed", ErrInvalidPaging)
+	}
+	id, err := strconv.Atoi(string(decoded))
+	if err != nil {
+		return 0, fmt.Errorf("%w, cursor is malformed", ErrInvalidPaging)
+	}
+	return id, nil
+}
+
+// Build ORDER BY and LIMIT of a list query. In cursor mode the cursor condition is
+// added to qb and one more row than the page size is fetched to know if a next page exists,
+// the orders of qb are only used in page mode.
+func buildPageQuery(page entity.Page_request, sortColumns map[string]string, idColumn string, qb *queryBuilder) (string, error) {
+	if page.Cursor_mode {
+		for _, sort := range page.Sort {
+			if sortColumns[sort.Field] != idColumn || sort.Desc {
+				return "", fmt.Errorf("%w, cursor paging is only sorted by id", ErrInvalidPaging)
+			}
+		}
+
+		if page.Cursor != "" {
+			lastId, err := decodeCursor(page.Cursor)
+			if err != nil {
+				return "", err
+			}
+			qb.where(idColumn+" > ?", lastId)
+		}
+
+		return fmt.Sprintf(" ORDER BY %s LIMIT %s", idColumn, qb.arg(page.Size+1)), nil
+	}
+
+	orders := append([]string{}, qb.orders...)
+	for _, sort := range page.Sort {
+		column, ok := sortColumns[sort.Field]
+		if !ok {
+			return "", fmt.Errorf("%w, can not sort by %s", ErrInvalidPaging, sort.Field)
+		}
+		if sort.Desc {
+			column += " DESC"
+		}
+		orders = append(orders, column)
+	}
+	// Id keeps the order stable between pages
+	orders = append(orders, idColumn)
+
+	return fmt.Sprintf(" ORDER BY %s LIMIT %s OFFSET %s", strings.Join(orders, ","), qb.arg(page.Size), qb.arg((page.Page-1)*page.Size)), nil
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"strings"
+	"submission-project-enigma-laundry/entity"
+	"testing"
+)
+
+func TestListTransactionPagesOverBills(t *testing.T) {
+	if strings.Contains(transactionListFromQuery, "transaction_detail") {
+		t.Fatalf("bill details joined into the paged rows %q", transactionListFromQuery)
+	}
+
+	qb := newTransactionQueryBuilder(TransactionFilter{ProductName: "Cuci"})
+	pageQuery, err := buildPageQuery(entity.Page_request{Page: 2, Size: 10}, transactionSortColumns, "t.transaction_id", &qb)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(qb.clause(), "EXISTS (SELECT 1 FROM transaction_detail") {
+		t.Fatalf("product filter is not an EXISTS %q", qb.clause())
+	}
+	if !strings.HasPrefix(pageQuery, " ORDER BY t.transaction_id LIMIT ") {
+		t.Fatalf("unexpected page query %q", pageQuery)
+	}
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"submission-project-enigma-laundry/entity"
+	_ "github.com/lib/pq"
+)
+
+type PaymentRepository interface {
+	CreatePayment(id int, payment *entity.Payment, transaction *entity.Transaction) (*entity.Payment, error)
+	GetPayments(id int) ([]entity.Payment, error)
+}
+
+// Returned when a payment is bigger than the remaining bill
+var ErrPaymentExceedsOutstanding = errors.New("payment exceeds outstanding balance")
+
+// Returned when paying a bill that was cancelled
+var ErrTransactionCancelled = errors.New("transaction is cancelled")
+
+// Grand total of transaction t, kept on the bill when it is made or edited
+const totalBillQuery = `t.total_bill`
+
+// Sum of every payment of transaction t
+const paidAmountQuery = `(SELECT COALESCE(SUM(pm.amount),0) FROM payment AS pm WHERE pm.transaction_id = t.transaction_id)`
+
+type paymentRepository struct {
+	DB *sql.DB
+}
+
+func NewPaymentRepo(db *sql.DB) PaymentRepository {
+	return &paymentRepository{DB: db}
+}
+
+func (pr *paymentRepository) CreatePayment(id int, payment *entity.Payment, transaction *entity.Transaction) (*entity.Payment, error) {
+	tx, err := pr.DB.Begin()
+	if err != nil {
+		err = fmt.Errorf("failed starting transaction , %s", err)
+		return payment, err
+	}
+
+	// Lock the bill so concurrent payments can not both pass the outstanding check
+	selectBill := "SELECT t.transaction_id,t.customer_id,t.status," + totalBillQuery + "," + paidAmountQuery + " FROM transaction AS t WHERE t.transaction_id = $1 FOR UPDATE"
+	err = tx.QueryRow(selectBill, id).Scan(&transaction.Transaction_id, &transaction.Customer_id, &transaction.Status, &transaction.Total_bill, &transaction.Paid_amount)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			err = errors.New("transaction not found")
+			return payment, err
+		}
+		return payment, err
+	}
+
+	if transaction.Status == entity.Status_cancelled {
+		tx.Rollback()
+		return payment, ErrTransactionCancelled
+	}
+
+	if payment.Amount > transaction.Total_bill-transaction.Paid_amount {
+		tx.Rollback()
+		err = fmt.Errorf("%w, outstanding is %d", ErrPaymentExceedsOutstanding, transaction.Total_bill-transaction.Paid_amount)
+		return payment, err
+	}
+
+	payment.Transaction_id = transaction.Transaction_id
+	err = insertPayment(tx, transaction.Customer_id, payment)
+	if err != nil {
+		tx.Rollback()
+		return payment, err
+	}
+
+	// Points are earned once the bill is paid off
+	if transaction.Paid_amount+payment.Amount == transaction.Total_bill {
+		err = earnPoints(tx, transaction.Transaction_id, transaction.Customer_id, transaction.Total_bill)
+		if err != nil {
+			tx.Rollback()
+			return payment, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		err = fmt.Errorf("failed commit transaction , %s", err)
+		return payment, err
+	}
+
+	transaction.Paid_amount += payment.Amount
+	transaction.Outstanding = transaction.Total_bill - transaction.Paid_amount
+	transaction.Payment_status = entity.GetPaymentStatus(transaction.Total_bill, transaction.Paid_amount)
+
+	return payment, nil
+}
+
+func (pr *paymentRepository) GetPayments(id int) ([]entity.Payment, error) {
+	return selectPayments(pr.DB, id)
+}
+
+func selectPayments(db *sql.DB, id int) ([]entity.Payment, error) {
+	payments := []entity.Payment{}
+
+	query := "SELECT payment_id,transaction_id,amount,method,payment_type,note,paid_at FROM payment WHERE transaction_id = $1 ORDER BY paid_at,payment_id"
+
+	rows, err := db.Query(query, id)
+	if err != nil {
+		return payments, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		payment := entity.Payment{}
+		err = rows.Scan(&payment.Payment_id, &payment.Transaction_id, &payment.Amount, &payment.Method, &payment.Payment_type, &payment.Note, &payment.Paid_at)
+		if err != nil {
+			return payments, err
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, rows.Err()
+}
+
+// Insert a payment inside an already running transaction, a wallet payment is debited from the customer wallet
+func insertPayment(tx *sql.Tx, customerId string, payment *entity.Payment) error {
+	createPayment := "INSERT INTO payment (transaction_id,amount,method,payment_type,note) VALUES ($1,$2,$3,$4,$5) RETURNING payment_id,paid_at"
+
+	err := tx.QueryRow(createPayment, payment.Transaction_id, payment.Amount, payment.Method, payment.Payment_type, payment.Note).Scan(&payment.Payment_id, &payment.Paid_at)
+	if err != nil {
+		err = fmt.Errorf("failed insert into payment , %s", err)
+		return err
+	}
+
+	if payment.Method == entity.Payment_method_wallet {
+		return debitWallet(tx, customerId, payment)
+	}
+	return nil
+}
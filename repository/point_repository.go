@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"submission-project-enigma-laundry/entity"
+	_ "github.com/lib/pq"
+)
+
+type PointRepository interface {
+	GetCustomerPoints(customerId int) (*entity.Customer_points, error)
+}
+
+// Returned when redeeming more points than the customer has
+var ErrNotEnoughPoints = errors.New("not enough points")
+
+// Returned when the redeemed points are worth more than the bill
+var ErrPointsExceedBill = errors.New("redeemed points exceed the bill")
+
+// Points earned on paid bills, and the part of it earned on bills cancelled later
+const earnedPointsQuery = `(SELECT COALESCE(SUM(cp.points),0) FROM customer_point AS cp WHERE cp.customer_id = $1 AND cp.entry_type = 'earn'),
+	(SELECT COALESCE(SUM(cp.points),0) FROM customer_point AS cp INNER JOIN transaction AS t ON cp.transaction_id = t.transaction_id
+	WHERE cp.customer_id = $1 AND cp.entry_type = 'earn' AND t.status = 'cancelled')`
+
+type pointRepository struct {
+	DB *sql.DB
+}
+
+func NewPointRepo(db *sql.DB) PointRepository {
+	return &pointRepository{DB: db}
+}
+
+func (pr *pointRepository) GetCustomerPoints(customerId int) (*entity.Customer_points, error) {
+	points := entity.Customer_points{Ledger: []entity.Point_entry{}}
+
+	earned, cancelled := 0, 0
+	query := "SELECT c.customer_id,(SELECT COALESCE(SUM(cp.points),0) FROM customer_point AS cp WHERE cp.customer_id = $1)," + earnedPointsQuery + " FROM customer AS c WHERE c.customer_id = $1"
+	err := pr.DB.QueryRow(query, customerId).Scan(&points.Customer_id, &points.Balance, &earned, &cancelled)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = errors.New("customer not found")
+			return &points, err
+		}
+		return &points, err
+	}
+	points.Lifetime_points = lifetimePoints(earned, cancelled)
+	points.Tier = entity.GetMembershipTier(points.Lifetime_points)
+	points.Next_tier = entity.GetNextMembershipTier(points.Tier.Code)
+
+	ledger := `SELECT cp.point_id,cp.customer_id,COALESCE(cp.transaction_id::text,''),COALESCE(t.bill_number,''),cp.points,cp.entry_type,cp.note,cp.created_at
+	FROM customer_point AS cp
+	LEFT JOIN transaction AS t ON cp.transaction_id = t.transaction_id
+	WHERE cp.customer_id = $1 ORDER BY cp.created_at DESC,cp.point_id DESC`
+
+	rows, err := pr.DB.Query(ledger, customerId)
+	if err != nil {
+		return &points, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		entry := entity.Point_entry{}
+		err = rows.Scan(&entry.Point_id, &entry.Customer_id, &entry.Transaction_id, &entry.Bill_number, &entry.Points, &entry.Entry_type, &entry.Note, &entry.Created_at)
+		if err != nil {
+			return &points, err
+		}
+		points.Ledger = append(points.Ledger, entry)
+	}
+
+	return &points, rows.Err()
+}
+
+// Lock the customer so the points balance can not be spent twice, returns the balance and lifetime points
+func lockCustomerPoints(tx *sql.Tx, customerId string) (int, int, error) {
+	balance, lifetime := 0, 0
+
+	_, err := tx.Exec("SELECT customer_id FROM customer WHERE customer_id = $1 FOR UPDATE", customerId)
+	if err != nil {
+		return balance, lifetime, err
+	}
+
+	earned, cancelled := 0, 0
+	query := "SELECT (SELECT COALESCE(SUM(cp.points),0) FROM customer_point AS cp WHERE cp.customer_id = $1)," + earnedPointsQuery
+	err = tx.QueryRow(query, customerId).Scan(&balance, &earned, &cancelled)
+	return balance, lifetimePoints(earned, cancelled), err
+}
+
+// Lifetime points reach the tier. A cancelled bill takes back all it earned here, also when
+// the points were already redeemed and the balance could only give back part of them
+func lifetimePoints(earned int, cancelled int) int {
+	return max(earned-cancelled, 0)
+}
+
+func insertPointEntry(tx *sql.Tx, entry *entity.Point_entry) error {
+	createEntry := "INSERT INTO customer_point (customer_id,transaction_id,points,entry_type,note) VALUES ($1,NULLIF($2,'')::int,$3,$4,$5) RETURNING point_id,created_at"
+
+	err := tx.QueryRow(createEntry, entry.Customer_id, entry.Transaction_id, entry.Points, entry.Entry_type, entry.Note).Scan(&entry.Point_id, &entry.Created_at)
+	if err != nil {
+		err = fmt.Errorf("failed insert into customer point , %s", err)
+		return err
+	}
+	return nil
+}
+
+// Points of a fully paid bill, only what was not earned on it before
+func earnPoints(tx *sql.Tx, transactionId string, customerId string, totalBill int) error {
+	earned := 0
+	err := tx.QueryRow("SELECT COALESCE(SUM(points),0) FROM customer_point WHERE transaction_id = $1 AND entry_type = $2", transactionId, entity.Point_earn).Scan(&earned)
+	if err != nil {
+		return err
+	}
+
+	due := entity.GetEarnedPoints(totalBill) - earned
+	if due <= 0 {
+		return nil
+	}
+	return insertPointEntry(tx, &entity.Point_entry{Customer_id: customerId, Transaction_id: transactionId, Points: due, Entry_type: entity.Point_earn, Note: "bill paid"})
+}
+
+// Give back the points redeemed on a cancelled bill and take back the points earned on it.
+// Earned points already redeemed elsewhere are only taken back up to the balance, it never goes negative
+func reverseBillPoints(tx *sql.Tx, transactionId string, customerId string) error {
+	balance, _, err := lockCustomerPoints(tx, customerId)
+	if err != nil {
+		return err
+	}
+
+	earned, spent := 0, 0
+	query := `SELECT COALESCE(SUM(points) FILTER (WHERE entry_type IN ('earn','reverse')),0),
+	COALESCE(-SUM(points) FILTER (WHERE entry_type IN ('redeem','refund')),0)
+	FROM customer_point WHERE transaction_id = $1`
+	err = tx.QueryRow(query, transactionId).Scan(&earned, &spent)
+	if err != nil {
+		return err
+	}
+
+	if spent > 0 {
+		err = insertPointEntry(tx, &entity.Point_entry{Customer_id: customerId, Transaction_id: transactionId, Points: spent, Entry_type: entity.Point_refund, Note: "bill cancelled"})
+		if err != nil {
+			return err
+		}
+		balance += spent
+	}
+
+	reversed := reversiblePoints(earned, balance)
+	if reversed > 0 {
+		note := "bill cancelled"
+		if reversed < earned {
+			note = fmt.Sprintf("bill cancelled, %d earned points were already redeemed", earned-reversed)
+		}
+		err = insertPointEntry(tx, &entity.Point_entry{Customer_id: customerId, Transaction_id: transactionId, Points: -reversed, Entry_type: entity.Point_reverse, Note: note})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Earned points taken back from a balance, at most the balance
+func reversiblePoints(earned int, balance int) int {
+	return max(min(earned, balance), 0)
+}
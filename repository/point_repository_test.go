@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"submission-project-enigma-laundry/entity"
+	"testing"
+)
+
+func TestReversiblePoints(t *testing.T) {
+	tests := []struct {
+		earned, balance, expected int
+	}{
+		{50, 120, 50},
+		{50, 50, 50},
+		{50, 20, 20},
+		{50, 0, 0},
+		{0, 120, 0},
+	}
+
+	for _, test := range tests {
+		if got := reversiblePoints(test.earned, test.balance); got != test.expected {
+			t.Fatalf("earned %d with balance %d expected %d, got %d", test.earned, test.balance, test.expected, got)
+		}
+	}
+}
+
+func TestTierAfterCappedCancel(t *testing.T) {
+	// 1200 points earned on a bill made the customer silver, 1100 of them were redeemed on other bills
+	earned, balance := 1200, 100
+	if tier := entity.GetMembershipTier(lifetimePoints(earned, 0)); tier.Code != entity.Membership_silver {
+		t.Fatalf("before cancel expected silver, got %s", tier.Code)
+	}
+
+	// Cancelling the bill can only take back the 100 points left
+	reversed := reversiblePoints(earned, balance)
+	if reversed != 100 || balance-reversed != 0 {
+		t.Fatalf("expected 100 points taken back, got %d", reversed)
+	}
+
+	// The tier still drops, the whole earning of the cancelled bill is left out of the lifetime points
+	lifetime := lifetimePoints(earned, earned)
+	if tier := entity.GetMembershipTier(lifetime); lifetime != 0 || tier.Code != entity.Membership_member {
+		t.Fatalf("after cancel expected member with 0 lifetime points, got %s with %d", tier.Code, lifetime)
+	}
+}
+
+func TestLifetimePoints(t *testing.T) {
+	tests := []struct {
+		earned, cancelled, expected int
+	}{
+		{0, 0, 0},
+		{1200, 0, 1200},
+		{1200, 200, 1000},
+		{1200, 1200, 0},
+		{100, 200, 0},
+	}
+
+	for _, test := range tests {
+		if got := lifetimePoints(test.earned, test.cancelled); got != test.expected {
+			t.Fatalf("earned %d with %d cancelled expected %d, got %d", test.earned, test.cancelled, test.expected, got)
+		}
+	}
+}
@@ -1,134 +1,255 @@
-package repository
-
-import (
-	"database/sql"
-	"errors"
-	"submission-project-enigma-laundry/entity"
-	_ "github.com/lib/pq"
-)
-
-type ProductRepository interface {
-	GetProduct() (*sql.Rows, error)
-	GetProductByName(name string) (*sql.Rows, error)
-	GetDetailProduct(id int, product *entity.Product) (*entity.Product, error)
-	IsProductExist(id int, product *entity.Product) (bool, error)
-	ProductInTransactionDetail(id int, transactionDetail *entity.Transaction_detail) (bool, error)
-	CreateProduct(product *entity.Product) (*entity.Product, error)
-	UpdateProduct(id int, product *entity.Product) (*entity.Product, error)
-	DeleteProduct(id int) (bool, error)
-}
-
-type productRepository struct {
-	DB *sql.DB
-}
-
-func NewProductRepo(db *sql.DB) ProductRepository {
-	return &productRepository{DB: db}
-}
-
-func (pr *productRepository) IsProductExist(id int, product *entity.Product) (bool, error) {
-	query := "SELECT product_id FROM product WHERE product_id = $1"
-
-	// Execute the query and scan the result
-	err := pr.DB.QueryRow(query, id).Scan(&product.Product_id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			// No product found
-			return false, nil // No error, just return false
-		}
-		// Return any other errors encountered
-		return false, err
-	}
-
-	// product exists
-	return true, nil
-}
-
-func (pr *productRepository) ProductInTransactionDetail(id int, transactionDetail *entity.Transaction_detail) (bool, error) {
-	query := "SELECT product_id FROM transaction_detail WHERE product_id = $1"
-
-	err := pr.DB.QueryRow(query, id).Scan(&transactionDetail.Product_id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			// No product found
-			return false, nil // No error, just return false
-		}
-		// Return any other errors encountered
-		return false, err
-	}
-
-	// product exists
-	return true, nil
-}
-
-
-func (pr *productRepository) CreateProduct(product *entity.Product) (*entity.Product, error) {
-	// insert product data into db
-	insert_query := "INSERT INTO product (product_name,unit,price) VALUES ($1, $2, $3) RETURNING product_id;"
-
-	err := pr.DB.QueryRow(insert_query, product.Product_name, product.Unit, product.Price).Scan(&product.Product_id)
-	if err != nil {
-		return product, err // Handle error if the query fails
-	}
-	return product, nil
-}
-
-func (pr *productRepository) GetProduct() (*sql.Rows, error) {
-	// Get all data from product table
-	select_all := "SELECT product_id,product_name,unit,price FROM product;"
-
-	rows, err := pr.DB.Query(select_all)
-	if err != nil {
-		return rows, err
-	}
-	return rows, nil
-}
-
-func (pr *productRepository) GetProductByName(name string) (*sql.Rows, error) {
-	// Get all data from product table base on name
-	query := "SELECT product_id,product_name,price,unit FROM product WHERE product_name LIKE $1;"
-
-	rows, err := pr.DB.Query(query, name)
-	if err != nil {
-		return rows, err
-	}
-	return rows, nil
-}
-
-func (pr *productRepository) GetDetailProduct(id int, product *entity.Product) (*entity.Product, error) {
-	select_by_id := "SELECT product_id,product_name,price,unit FROM product WHERE product_id = $1"
-
-	err := pr.DB.QueryRow(select_by_id, id).Scan(&product.Product_id, &product.Product_name, &product.Price, &product.Unit)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			err = errors.New("product not found")
-			return product, err
-		}
-
-		return product, err
-	}
-
-	return product, nil
-}
-
-func (pr *productRepository) UpdateProduct(id int, product *entity.Product) (*entity.Product, error) {
-	update := "UPDATE product SET product_name = $2,unit = $3,price = $4 WHERE product_id = $1"
-
-	_, err := pr.DB.Exec(update, id, product.Product_name, product.Unit, product.Price)
-	if err != nil {
-		return product, err
-	}
-	return product, nil
-}
-
-func (pr *productRepository) DeleteProduct(id int) (bool, error) {
-	query := "DELETE FROM product WHERE product_id = $1"
-	// Execute the query and scan the result
-	_, err := pr.DB.Exec(query, id)
-	if err != nil {
-		// Return any other errors encountered
-		return false, err
-	}
-
-	return true, nil
-}
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"submission-project-enigma-laundry/entity"
+	"github.com/lib/pq"
+)
+
+type ProductRepository interface {
+	GetProduct(filter ListFilter, page entity.Page_request) (*sql.Rows, int, error)
+	GetDetailProduct(id int, product *entity.Product) (*entity.Product, error)
+	IsProductExist(id int, product *entity.Product) (bool, error)
+	CreateProduct(product *entity.Product) (*entity.Product, error)
+	UpdateProduct(id int, product *entity.Product) (*entity.Product, error)
+	DeleteProduct(id int) (bool, error)
+	RestoreProduct(id int) (bool, error)
+	GetProductPrices(id int) ([]entity.Product_price, error)
+}
+
+// Products covered by a package, empty for a service
+const coveredProductsQuery = `ARRAY(SELECT pp.product_id::text FROM package_product AS pp WHERE pp.package_id = product.product_id ORDER BY pp.product_id)`
+
+// Price of product (a table name or alias) on date, the latest one effective by then.
+// A date before the first price gets the first price. The price history is the only source of prices
+func priceOnQuery(product string, date string) string {
+	return `COALESCE((SELECT ph.price FROM product_price AS ph WHERE ph.product_id = ` + product + `.product_id AND ph.effective_from <= ` + date + ` ORDER BY ph.effective_from DESC LIMIT 1),
+	(SELECT ph.price FROM product_price AS ph WHERE ph.product_id = ` + product + `.product_id ORDER BY ph.effective_from LIMIT 1))`
+}
+
+// Price of the product table charged today
+var productPriceQuery = priceOnQuery("product", "CURRENT_DATE")
+
+type productRepository struct {
+	DB *sql.DB
+}
+
+func NewProductRepo(db *sql.DB) ProductRepository {
+	return &productRepository{DB: db}
+}
+
+func (pr *productRepository) IsProductExist(id int, product *entity.Product) (bool, error) {
+	// Deleted products can not be used anymore
+	query := "SELECT product_id FROM product WHERE product_id = $1 AND deleted_at IS NULL"
+
+	// Execute the query and scan the result
+	err := pr.DB.QueryRow(query, id).Scan(&product.Product_id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// No product found
+			return false, nil // No error, just return false
+		}
+		// Return any other errors encountered
+		return false, err
+	}
+
+	// product exists
+	return true, nil
+}
+
+func (pr *productRepository) CreateProduct(product *entity.Product) (*entity.Product, error) {
+	tx, err := pr.DB.Begin()
+	if err != nil {
+		err = fmt.Errorf("failed starting transaction , %s", err)
+		return product, err
+	}
+
+	// insert product data into db
+	insert_query := "INSERT INTO product (product_name,unit,pricing_model,min_weight,tax_inclusive,product_type,quota,category_id,description,sort_order,active) VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8,'')::int, $9, $10, $11) RETURNING product_id;"
+
+	err = tx.QueryRow(insert_query, product.Product_name, product.Unit, product.Pricing_model, product.Min_weight, product.Tax_inclusive, product.Product_type, product.Quota, product.Category_id, product.Description, product.Sort_order, product.Active).Scan(&product.Product_id)
+	if err != nil {
+		tx.Rollback()
+		return product, err // Handle error if the query fails
+	}
+
+	err = insertPackageProducts(tx, product)
+	if err != nil {
+		tx.Rollback()
+		return product, err
+	}
+
+	// The first price is effective from the day the product is made
+	_, err = tx.Exec("INSERT INTO product_price (product_id,price,effective_from) VALUES ($1,$2,CURRENT_DATE)", product.Product_id, product.Price)
+	if err != nil {
+		tx.Rollback()
+		return product, fmt.Errorf("failed insert into product price , %s", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		err = fmt.Errorf("failed commit transaction , %s", err)
+		return product, err
+	}
+	return product, nil
+}
+
+func (pr *productRepository) GetProduct(filter ListFilter, page entity.Page_request) (*sql.Rows, int, error) {
+	qb := queryBuilder{}
+	productSearchColumns.filter(filter, page, &qb)
+	if filter.CategoryId != 0 {
+		qb.where("category_id = ?", filter.CategoryId)
+	}
+
+	total := 0
+	err := pr.DB.QueryRow("SELECT COUNT(*) FROM product"+qb.clause(), qb.args...).Scan(&total)
+	if err != nil {
+		return nil, total, err
+	}
+
+	pageQuery, err := buildPageQuery(page, productSortColumns, "product_id", &qb)
+	if err != nil {
+		return nil, total, err
+	}
+
+	// Get one page of data from product table
+	select_all := "SELECT product_id,product_name,unit," + productPriceQuery + ",pricing_model,min_weight,tax_inclusive,product_type,quota," + coveredProductsQuery + ",COALESCE(category_id::text,''),description,sort_order,active,deleted_at FROM product" + qb.clause() + pageQuery
+
+	rows, err := pr.DB.Query(select_all, qb.args...)
+	if err != nil {
+		return rows, total, err
+	}
+	return rows, total, nil
+}
+
+func (pr *productRepository) GetDetailProduct(id int, product *entity.Product) (*entity.Product, error) {
+	select_by_id := "SELECT product_id,product_name," + productPriceQuery + ",unit,pricing_model,min_weight,tax_inclusive,product_type,quota," + coveredProductsQuery + ",COALESCE(category_id::text,''),description,sort_order,active,deleted_at FROM product WHERE product_id = $1"
+
+	err := pr.DB.QueryRow(select_by_id, id).Scan(&product.Product_id, &product.Product_name, &product.Price, &product.Unit, &product.Pricing_model, &product.Min_weight, &product.Tax_inclusive, &product.Product_type, &product.Quota, pq.Array(&product.Covered_product_ids), &product.Category_id, &product.Description, &product.Sort_order, &product.Active, &product.Deleted_at)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = errors.New("product not found")
+			return product, err
+		}
+
+		return product, err
+	}
+
+	return product, nil
+}
+
+func (pr *productRepository) UpdateProduct(id int, product *entity.Product) (*entity.Product, error) {
+	tx, err := pr.DB.Begin()
+	if err != nil {
+		err = fmt.Errorf("failed starting transaction , %s", err)
+		return product, err
+	}
+
+	// A new price only goes to the price history
+	update := "UPDATE product SET product_name = $2,unit = $3,pricing_model = $4,min_weight = $5,tax_inclusive = $6,product_type = $7,quota = $8,category_id = NULLIF($9,'')::int,description = $10,sort_order = $11,active = $12,updated_at = CURRENT_TIMESTAMP WHERE product_id = $1"
+
+	_, err = tx.Exec(update, id, product.Product_name, product.Unit, product.Pricing_model, product.Min_weight, product.Tax_inclusive, product.Product_type, product.Quota,
+		product.Category_id, product.Description, product.Sort_order, product.Active)
+	if err != nil {
+		tx.Rollback()
+		return product, err
+	}
+
+	// Only a price that differs from the one effective on that date is added to the history
+	insertPrice := `INSERT INTO product_price (product_id,price,effective_from) SELECT product_id,$2,$3::date FROM product
+	WHERE product_id = $1 AND $2 IS DISTINCT FROM ` + priceOnQuery("product", "$3::date") + `
+	ON CONFLICT (product_id,effective_from) DO UPDATE SET price = EXCLUDED.price`
+	_, err = tx.Exec(insertPrice, id, product.Price, product.Price_effective_from)
+	if err != nil {
+		tx.Rollback()
+		return product, fmt.Errorf("failed insert into product price , %s", err)
+	}
+
+	err = tx.QueryRow("SELECT "+productPriceQuery+" FROM product WHERE product_id = $1", id).Scan(&product.Price)
+	if err != nil {
+		tx.Rollback()
+		return product, err
+	}
+
+	_, err = tx.Exec("DELETE FROM package_product WHERE package_id = $1", id)
+	if err != nil {
+		tx.Rollback()
+		return product, err
+	}
+
+	err = insertPackageProducts(tx, product)
+	if err != nil {
+		tx.Rollback()
+		return product, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		err = fmt.Errorf("failed commit transaction , %s", err)
+		return product, err
+	}
+	return product, nil
+}
+
+// Product is only marked deleted so the bills and subscriptions that have it stay
+func (pr *productRepository) DeleteProduct(id int) (bool, error) {
+	query := "UPDATE product SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE product_id = $1 AND deleted_at IS NULL"
+	// Execute the query and scan the result
+	_, err := pr.DB.Exec(query, id)
+	if err != nil {
+		// Return any other errors encountered
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Returns false when the product does not exist
+func (pr *productRepository) RestoreProduct(id int) (bool, error) {
+	result, err := pr.DB.Exec("UPDATE product SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE product_id = $1", id)
+	if err != nil {
+		return false, err
+	}
+	restored, err := result.RowsAffected()
+	return restored == 1, err
+}
+
+func insertPackageProducts(tx *sql.Tx, product *entity.Product) error {
+	for _, productId := range product.Covered_product_ids {
+		_, err := tx.Exec("INSERT INTO package_product (package_id,product_id) VALUES ($1,$2) ON CONFLICT DO NOTHING", product.Product_id, productId)
+		if err != nil {
+			return fmt.Errorf("failed insert into package product , %s", err)
+		}
+	}
+	return nil
+}
+
+// Price history of the product, the latest effective date first
+func (pr *productRepository) GetProductPrices(id int) ([]entity.Product_price, error) {
+	prices := []entity.Product_price{}
+
+	query := `SELECT ph.product_price_id,ph.product_id,ph.price,ph.effective_from,ph.created_at,
+	ph.effective_from = (SELECT MAX(c.effective_from) FROM product_price AS c WHERE c.product_id = ph.product_id AND c.effective_from <= CURRENT_DATE)
+	FROM product_price AS ph WHERE ph.product_id = $1 ORDER BY ph.effective_from DESC`
+	rows, err := pr.DB.Query(query, id)
+	if err != nil {
+		return prices, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		price := entity.Product_price{}
+		var current sql.NullBool
+		err = rows.Scan(&price.Product_price_id, &price.Product_id, &price.Price, &price.Effective_from, &price.Created_at, &current)
+		if err != nil {
+			return prices, err
+		}
+		price.Current = current.Bool
+		prices = append(prices, price)
+	}
+
+	return prices, rows.Err()
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"submission-project-enigma-laundry/entity"
+	"github.com/lib/pq"
+)
+
+type PromoRepository interface {
+	GetPromos() ([]entity.Promo, error)
+	GetPromo(code string) (*entity.Promo, error)
+	CreatePromo(promo *entity.Promo) (*entity.Promo, error)
+	UpdatePromo(code string, promo *entity.Promo) (*entity.Promo, error)
+}
+
+// Returned when no promo has the requested code
+var ErrPromoNotFound = errors.New("promo not found")
+
+// Returned when the code already belongs to another promo
+var ErrPromoCodeTaken = errors.New("promo code is already taken")
+
+// Promo with the products it is restricted to, pr is the promo table
+const selectPromoQuery = `SELECT pr.promo_id,pr.code,pr.name,pr.discount_type,pr.discount_value,pr.max_discount,pr.min_spend,pr.starts_at,pr.ends_at,pr.usage_limit,
+	ARRAY(SELECT pp.product_id::text FROM promo_product AS pp WHERE pp.promo_id = pr.promo_id ORDER BY pp.product_id)
+	FROM promo AS pr`
+
+type promoRepository struct {
+	DB *sql.DB
+}
+
+func NewPromoRepo(db *sql.DB) PromoRepository {
+	return &promoRepository{DB: db}
+}
+
+func scanPromo(row interface{ Scan(dest ...any) error }, promo *entity.Promo) error {
+	return row.Scan(&promo.Promo_id, &promo.Code, &promo.Name, &promo.Discount_type, &promo.Discount_value, &promo.Max_discount, &promo.Min_spend, &promo.Starts_at, &promo.Ends_at, &promo.Usage_limit, pq.Array(&promo.Product_ids))
+}
+
+func (pr *promoRepository) GetPromos() ([]entity.Promo, error) {
+	promos := []entity.Promo{}
+
+	rows, err := pr.DB.Query(selectPromoQuery + " ORDER BY pr.ends_at DESC,pr.promo_id")
+	if err != nil {
+		return promos, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		promo := entity.Promo{}
+		err = scanPromo(rows, &promo)
+		if err != nil {
+			return promos, err
+		}
+		promos = append(promos, promo)
+	}
+
+	return promos, rows.Err()
+}
+
+func (pr *promoRepository) GetPromo(code string) (*entity.Promo, error) {
+	promo := entity.Promo{}
+
+	err := scanPromo(pr.DB.QueryRow(selectPromoQuery+" WHERE pr.code = $1", code), &promo)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &promo, ErrPromoNotFound
+		}
+		return &promo, err
+	}
+
+	return &promo, nil
+}
+
+func (pr *promoRepository) CreatePromo(promo *entity.Promo) (*entity.Promo, error) {
+	tx, err := pr.DB.Begin()
+	if err != nil {
+		err = fmt.Errorf("failed starting transaction , %s", err)
+		return promo, err
+	}
+
+	query := `INSERT INTO promo (code,name,discount_type,discount_value,max_discount,min_spend,starts_at,ends_at,usage_limit)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING promo_id`
+
+	err = tx.QueryRow(query, promo.Code, promo.Name, promo.Discount_type, promo.Discount_value, promo.Max_discount, promo.Min_spend, promo.Starts_at, promo.Ends_at, promo.Usage_limit).Scan(&promo.Promo_id)
+	if err != nil {
+		tx.Rollback()
+		return promo, promoError(err)
+	}
+
+	err = insertPromoProducts(tx, promo)
+	if err != nil {
+		tx.Rollback()
+		return promo, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		err = fmt.Errorf("failed commit transaction , %s", err)
+		return promo, err
+	}
+
+	return promo, nil
+}
+
+// Changes only apply to bills made after the update, old bills keep their discount
+func (pr *promoRepository) UpdatePromo(code string, promo *entity.Promo) (*entity.Promo, error) {
+	tx, err := pr.DB.Begin()
+	if err != nil {
+		err = fmt.Errorf("failed starting transaction , %s", err)
+		return promo, err
+	}
+
+	query := `UPDATE promo SET name = $2,discount_type = $3,discount_value = $4,max_discount = $5,min_spend = $6,starts_at = $7,ends_at = $8,usage_limit = $9,updated_at = CURRENT_TIMESTAMP
+	WHERE code = $1 RETURNING promo_id,code`
+
+	err = tx.QueryRow(query, code, promo.Name, promo.Discount_type, promo.Discount_value, promo.Max_discount, promo.Min_spend, promo.Starts_at, promo.Ends_at, promo.Usage_limit).Scan(&promo.Promo_id, &promo.Code)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return promo, ErrPromoNotFound
+		}
+		return promo, err
+	}
+
+	_, err = tx.Exec("DELETE FROM promo_product WHERE promo_id = $1", promo.Promo_id)
+	if err != nil {
+		tx.Rollback()
+		return promo, err
+	}
+
+	err = insertPromoProducts(tx, promo)
+	if err != nil {
+		tx.Rollback()
+		return promo, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		err = fmt.Errorf("failed commit transaction , %s", err)
+		return promo, err
+	}
+
+	return promo, nil
+}
+
+func insertPromoProducts(tx *sql.Tx, promo *entity.Promo) error {
+	for _, productId := range promo.Product_ids {
+		_, err := tx.Exec("INSERT INTO promo_product (promo_id,product_id) VALUES ($1,$2) ON CONFLICT DO NOTHING", promo.Promo_id, productId)
+		if err != nil {
+			return fmt.Errorf("failed insert into promo product , %s", err)
+		}
+	}
+	return nil
+}
+
+func promoError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrPromoCodeTaken
+	}
+	return err
+}
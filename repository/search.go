@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"strings"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/utils"
+)
+
+// Part of a phone number searched when q is only digits, ex : 4567
+const minPhoneDigits = 4
+
+// Filter of the customer, employee and product lists, deleted rows are left out unless IncludeDeleted
+type ListFilter struct {
+	Q              string
+	IncludeDeleted bool
+	// Products only, zero is every category
+	CategoryId int
+}
+
+// Columns searched by the q param of a list, they must stay the same as the search indexes in DDL.sql
+type searchColumns struct {
+	document  string // matched word by word with full text search
+	name      string // matched by part and by similarity, so typos are found
+	phone     string // empty when the list has no phone number
+	deletedAt string
+}
+
+var customerSearchColumns = searchColumns{
+	document:  "name || ' ' || COALESCE(address,'')",
+	name:      "name",
+	phone:     "phone_number",
+	deletedAt: "deleted_at",
+}
+
+var employeeSearchColumns = searchColumns{
+	document:  "e.name || ' ' || COALESCE(e.address,'') || ' ' || COALESCE(e.username,'')",
+	name:      "e.name",
+	phone:     "e.phone_number",
+	deletedAt: "e.deleted_at",
+}
+
+var productSearchColumns = searchColumns{
+	document:  "product_name",
+	name:      "product_name",
+	deletedAt: "deleted_at",
+}
+
+func (sc searchColumns) filter(filter ListFilter, page entity.Page_request, qb *queryBuilder) {
+	if !filter.IncludeDeleted {
+		qb.where(sc.deletedAt + " IS NULL")
+	}
+	sc.search(filter.Q, page, qb)
+}
+
+// Add the q condition to qb, case and accent insensitive. An exact phone number comes first,
+// then without a requested sort the most similar names.
+func (sc searchColumns) search(q string, page entity.Page_request, qb *queryBuilder) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return
+	}
+
+	query := qb.arg(q)
+	conditions := []string{
+		"to_tsvector('simple', search_normalize(" + sc.document + ")) @@ plainto_tsquery('simple', search_normalize(" + query + "))",
+		"search_normalize(" + sc.name + ") LIKE search_normalize(" + qb.arg(likePattern(q)) + ")",
+		"search_normalize(" + query + ") <% search_normalize(" + sc.name + ")",
+	}
+
+	if sc.phone != "" {
+		phoneNumber, err := utils.NormalizePhoneNumber(q)
+		if err == nil {
+			phone := qb.arg(phoneNumber)
+			conditions = append(conditions, sc.phone+" = "+phone)
+			qb.orders = append(qb.orders, "("+sc.phone+" = "+phone+") DESC")
+		}
+		digits, ok := phoneDigits(q)
+		if ok && len(digits) >= minPhoneDigits {
+			conditions = append(conditions, sc.phone+" LIKE "+qb.arg(likePattern(digits)))
+		}
+	}
+
+	qb.where("(" + strings.Join(conditions, " OR ") + ")")
+
+	if len(page.Sort) == 0 {
+		qb.orders = append(qb.orders, "word_similarity(search_normalize("+query+"), search_normalize("+sc.name+")) DESC")
+	}
+}
+
+// Digits of a q written like a phone number, the leading 0 of a local number is dropped
+// because saved numbers start with the country code
+func phoneDigits(q string) (string, bool) {
+	digits := strings.Builder{}
+	for _, r := range q {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case strings.ContainsRune("+ -.()", r):
+		default:
+			return "", false
+		}
+	}
+	return strings.TrimPrefix(digits.String(), "0"), true
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"strings"
+	"submission-project-enigma-laundry/entity"
+	"testing"
+)
+
+func TestSearchRanksExactPhoneNumberFirst(t *testing.T) {
+	qb := queryBuilder{}
+	customerSearchColumns.search("0812-3456-1234", entity.Page_request{Page: 1, Size: 10}, &qb)
+
+	pageQuery, err := buildPageQuery(entity.Page_request{Page: 1, Size: 10}, customerSortColumns, "customer_id", &qb)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(qb.clause(), "phone_number = $3") || !strings.Contains(qb.clause(), "phone_number LIKE $4") {
+		t.Fatalf("unexpected query %q", qb.clause())
+	}
+	if !strings.HasPrefix(pageQuery, " ORDER BY (phone_number = $3) DESC,word_similarity(search_normalize($1), search_normalize(name)) DESC,customer_id") {
+		t.Fatalf("unexpected order %q", pageQuery)
+	}
+
+	expectedArgs := []interface{}{"0812-3456-1234", "%0812-3456-1234%", "+6281234561234", "%81234561234%", 10, 0}
+	if len(qb.args) != len(expectedArgs) {
+		t.Fatalf("expected %d args, got %d", len(expectedArgs), len(qb.args))
+	}
+	for i := range expectedArgs {
+		if qb.args[i] != expectedArgs[i] {
+			t.Fatalf("arg %d expected %v, got %v", i, expectedArgs[i], qb.args[i])
+		}
+	}
+}
+
+func TestSearchKeepsRequestedSort(t *testing.T) {
+	page := entity.Page_request{Page: 1, Size: 10, Sort: []entity.Sort_field{{Field: "price", Desc: true}}}
+	qb := queryBuilder{}
+	productSearchColumns.search("Cuci Kering", page, &qb)
+
+	pageQuery, err := buildPageQuery(page, productSortColumns, "product_id", &qb)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(qb.clause(), "phone") || !strings.HasPrefix(pageQuery, " ORDER BY "+productPriceQuery+" DESC,product_id") {
+		t.Fatalf("unexpected query %q %q", qb.clause(), pageQuery)
+	}
+}
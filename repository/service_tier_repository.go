@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"submission-project-enigma-laundry/entity"
+	"github.com/lib/pq"
+)
+
+type ServiceTierRepository interface {
+	GetServiceTiers() ([]entity.Service_tier, error)
+	GetServiceTier(code string) (*entity.Service_tier, error)
+	CreateServiceTier(tier *entity.Service_tier) (*entity.Service_tier, error)
+	UpdateServiceTier(code string, tier *entity.Service_tier) (*entity.Service_tier, error)
+}
+
+// Returned when no service tier has the requested code
+var ErrServiceTierNotFound = errors.New("service tier not found")
+
+// Returned when the code already belongs to another service tier
+var ErrServiceTierTaken = errors.New("service tier code is already taken")
+
+type serviceTierRepository struct {
+	DB *sql.DB
+}
+
+func NewServiceTierRepo(db *sql.DB) ServiceTierRepository {
+	return &serviceTierRepository{DB: db}
+}
+
+func (sr *serviceTierRepository) GetServiceTiers() ([]entity.Service_tier, error) {
+	tiers := []entity.Service_tier{}
+
+	query := "SELECT service_tier_id,code,name,surcharge_type,surcharge_value,turnaround_hours FROM service_tier ORDER BY turnaround_hours DESC,service_tier_id"
+
+	rows, err := sr.DB.Query(query)
+	if err != nil {
+		return tiers, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		tier := entity.Service_tier{}
+		err = rows.Scan(&tier.Service_tier_id, &tier.Code, &tier.Name, &tier.Surcharge_type, &tier.Surcharge_value, &tier.Turnaround_hours)
+		if err != nil {
+			return tiers, err
+		}
+		tiers = append(tiers, tier)
+	}
+
+	return tiers, rows.Err()
+}
+
+func (sr *serviceTierRepository) GetServiceTier(code string) (*entity.Service_tier, error) {
+	tier := entity.Service_tier{}
+
+	query := "SELECT service_tier_id,code,name,surcharge_type,surcharge_value,turnaround_hours FROM service_tier WHERE code = $1"
+
+	err := sr.DB.QueryRow(query, code).Scan(&tier.Service_tier_id, &tier.Code, &tier.Name, &tier.Surcharge_type, &tier.Surcharge_value, &tier.Turnaround_hours)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &tier, ErrServiceTierNotFound
+		}
+		return &tier, err
+	}
+
+	return &tier, nil
+}
+
+func (sr *serviceTierRepository) CreateServiceTier(tier *entity.Service_tier) (*entity.Service_tier, error) {
+	query := "INSERT INTO service_tier (code,name,surcharge_type,surcharge_value,turnaround_hours) VALUES ($1,$2,$3,$4,$5) RETURNING service_tier_id"
+
+	err := sr.DB.QueryRow(query, tier.Code, tier.Name, tier.Surcharge_type, tier.Surcharge_value, tier.Turnaround_hours).Scan(&tier.Service_tier_id)
+	if err != nil {
+		return tier, serviceTierError(err)
+	}
+	return tier, nil
+}
+
+// Bills keep the surcharge they were made with, only new bills use the updated tier
+func (sr *serviceTierRepository) UpdateServiceTier(code string, tier *entity.Service_tier) (*entity.Service_tier, error) {
+	query := "UPDATE service_tier SET name = $2,surcharge_type = $3,surcharge_value = $4,turnaround_hours = $5,updated_at = CURRENT_TIMESTAMP WHERE code = $1 RETURNING service_tier_id,code"
+
+	err := sr.DB.QueryRow(query, code, tier.Name, tier.Surcharge_type, tier.Surcharge_value, tier.Turnaround_hours).Scan(&tier.Service_tier_id, &tier.Code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return tier, ErrServiceTierNotFound
+		}
+		return tier, err
+	}
+	return tier, nil
+}
+
+func serviceTierError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrServiceTierTaken
+	}
+	return err
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"submission-project-enigma-laundry/entity"
+	"time"
+	"github.com/lib/pq"
+)
+
+type SubscriptionRepository interface {
+	GetSubscriptions(customerId int) ([]entity.Subscription, error)
+	CreateSubscription(subscription *entity.Subscription) (*entity.Subscription, error)
+}
+
+// Returned when subscribing to a product that is not a package
+var ErrPackageNotFound = errors.New("package not found")
+
+const selectSubscriptionQuery = `SELECT s.subscription_id,s.customer_id,s.package_id,p.product_name,s.start_date,s.end_date,s.quota,s.used,s.price,
+	ARRAY(SELECT pp.product_id::text FROM package_product AS pp WHERE pp.package_id = s.package_id ORDER BY pp.product_id)
+	FROM customer_subscription AS s
+	INNER JOIN product AS p ON s.package_id = p.product_id`
+
+type subscriptionRepository struct {
+	DB *sql.DB
+}
+
+func NewSubscriptionRepo(db *sql.DB) SubscriptionRepository {
+	return &subscriptionRepository{DB: db}
+}
+
+// Every subscription of the customer, the latest first
+func (sr *subscriptionRepository) GetSubscriptions(customerId int) ([]entity.Subscription, error) {
+	subscriptions := []entity.Subscription{}
+
+	rows, err := sr.DB.Query(selectSubscriptionQuery+" WHERE s.customer_id = $1 ORDER BY s.end_date DESC,s.subscription_id DESC", customerId)
+	if err != nil {
+		return subscriptions, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		subscription := entity.Subscription{}
+		err = rows.Scan(&subscription.Subscription_id, &subscription.Customer_id, &subscription.Package_id, &subscription.Package_name, &subscription.Start_date, &subscription.End_date,
+			&subscription.Quota, &subscription.Used, &subscription.Price, pq.Array(&subscription.Covered_product_ids))
+		if err != nil {
+			return subscriptions, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// Quota is taken from the package when the customer subscribes, the price effective on the start date.
+// The price is debited from the wallet of the customer in the same transaction
+func (sr *subscriptionRepository) CreateSubscription(subscription *entity.Subscription) (*entity.Subscription, error) {
+	tx, err := sr.DB.Begin()
+	if err != nil {
+		err = fmt.Errorf("failed starting transaction , %s", err)
+		return subscription, err
+	}
+
+	selectPackage := "SELECT product_name,quota," + priceOnQuery("product", "$3::date") + "," + coveredProductsQuery + " FROM product WHERE product_id = $1 AND product_type = $2 AND deleted_at IS NULL"
+	err = tx.QueryRow(selectPackage, subscription.Package_id, entity.Product_type_package, subscription.Start_date).Scan(&subscription.Package_name, &subscription.Quota, &subscription.Price, pq.Array(&subscription.Covered_product_ids))
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return subscription, fmt.Errorf("%w, product %s is not a package", ErrPackageNotFound, subscription.Package_id)
+		}
+		return subscription, err
+	}
+
+	createSubscription := `INSERT INTO customer_subscription (customer_id,package_id,start_date,end_date,quota,price)
+	VALUES ($1,$2,$3,$4,$5,$6) RETURNING subscription_id`
+	err = tx.QueryRow(createSubscription, subscription.Customer_id, subscription.Package_id, subscription.Start_date, subscription.End_date, subscription.Quota, subscription.Price).Scan(&subscription.Subscription_id)
+	if err != nil {
+		tx.Rollback()
+		err = fmt.Errorf("failed insert into customer subscription , %s", err)
+		return subscription, err
+	}
+
+	if subscription.Price > 0 {
+		entry := entity.Wallet_entry{Customer_id: subscription.Customer_id, Amount: -subscription.Price, Entry_type: entity.Wallet_debit, Note: "subscription " + subscription.Subscription_id + " " + subscription.Package_name}
+		err = insertWalletEntry(tx, &entry)
+		if err != nil {
+			tx.Rollback()
+			return subscription, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		err = fmt.Errorf("failed commit transaction , %s", err)
+		return subscription, err
+	}
+
+	return subscription, nil
+}
+
+// Covered weight of a bill detail comes from one active subscription of the customer covering
+// its product, the one ending first. The subscription stays locked until the bill is saved
+func useQuota(tx *sql.Tx, customerId string, entryDate time.Time, billDetail *entity.Transaction_detail) error {
+	billDetail.Subscription_id = ""
+	billDetail.Quota_used = 0
+	if billDetail.Product.Pricing_model != entity.Pricing_per_kg {
+		return nil
+	}
+
+	remaining := 0.0
+	query := `SELECT s.subscription_id,s.quota - s.used FROM customer_subscription AS s
+	INNER JOIN package_product AS pp ON s.package_id = pp.package_id
+	WHERE s.customer_id = $1 AND pp.product_id = $2 AND $3::date BETWEEN s.start_date AND s.end_date AND s.used < s.quota
+	ORDER BY s.end_date,s.subscription_id LIMIT 1 FOR UPDATE OF s`
+	err := tx.QueryRow(query, customerId, billDetail.Product_id, entryDate).Scan(&billDetail.Subscription_id, &remaining)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	billDetail.Quota_used = entity.GetCoveredQty(billDetail.Qty, remaining)
+	billDetail.Line_total = entity.GetOverageLineTotal(billDetail.Product, billDetail.Qty, billDetail.Quota_used)
+
+	_, err = tx.Exec("UPDATE customer_subscription SET used = used + $2 WHERE subscription_id = $1", billDetail.Subscription_id, billDetail.Quota_used)
+	if err != nil {
+		err = fmt.Errorf("failed update customer subscription , %s", err)
+		return err
+	}
+	return nil
+}
+
+// Give back the quota the bill details of a bill used
+func releaseQuota(tx *sql.Tx, transactionId string) error {
+	release := `UPDATE customer_subscription AS s SET used = GREATEST(s.used - q.quota_used, 0)
+	FROM (SELECT subscription_id,SUM(quota_used) AS quota_used FROM transaction_detail WHERE transaction_id = $1 AND removed_at IS NULL AND subscription_id IS NOT NULL GROUP BY subscription_id) AS q
+	WHERE s.subscription_id = q.subscription_id`
+	_, err := tx.Exec(release, transactionId)
+	if err != nil {
+		err = fmt.Errorf("failed release customer subscription quota , %s", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"submission-project-enigma-laundry/entity"
+	"github.com/lib/pq"
+)
+
+type SupplyRepository interface {
+	GetSupplies(lowStockOnly bool) ([]entity.Supply, error)
+	GetSupply(id int) (*entity.Supply, error)
+	CreateSupply(supply *entity.Supply) (*entity.Supply, error)
+	UpdateSupply(id int, supply *entity.Supply) (*entity.Supply, error)
+	AdjustStock(movement *entity.Stock_movement) (*entity.Stock_movement, error)
+	GetStockMovements(id int) ([]entity.Stock_movement, error)
+	GetProductSupplies(productId int) ([]entity.Product_supply, error)
+	SetProductSupplies(productId int, supplies []entity.Product_supply) ([]entity.Product_supply, error)
+}
+
+// Returned when no supply has the requested id
+var ErrSupplyNotFound = errors.New("supply not found")
+
+// Returned when the name already belongs to another supply
+var ErrSupplyNameTaken = errors.New("supply name is already taken")
+
+// Returned when a stock adjustment takes more than the stock
+var ErrStockBelowZero = errors.New("stock can not go below zero")
+
+const selectSupplyQuery = "SELECT supply_id,name,unit,stock,reorder_level,stock <= reorder_level FROM supply"
+
+type supplyRepository struct {
+	DB *sql.DB
+}
+
+func NewSupplyRepo(db *sql.DB) SupplyRepository {
+	return &supplyRepository{DB: db}
+}
+
+// Every supply by name, the low stock report has the supplies at or below their reorder level
+// with the furthest below first
+func (sr *supplyRepository) GetSupplies(lowStockOnly bool) ([]entity.Supply, error) {
+	supplies := []entity.Supply{}
+
+	query := selectSupplyQuery + " ORDER BY name"
+	if lowStockOnly {
+		query = selectSupplyQuery + " WHERE stock <= reorder_level ORDER BY stock - reorder_level,name"
+	}
+
+	rows, err := sr.DB.Query(query)
+	if err != nil {
+		return supplies, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		supply := entity.Supply{}
+		err = rows.Scan(&supply.Supply_id, &supply.Name, &supply.Unit, &supply.Stock, &supply.Reorder_level, &supply.Low_stock)
+		if err != nil {
+			return supplies, err
+		}
+		supplies = append(supplies, supply)
+	}
+
+	return supplies, rows.Err()
+}
+
+func (sr *supplyRepository) GetSupply(id int) (*entity.Supply, error) {
+	supply := entity.Supply{}
+
+	err := sr.DB.QueryRow(selectSupplyQuery+" WHERE supply_id = $1", id).Scan(&supply.Supply_id, &supply.Name, &supply.Unit, &supply.Stock, &supply.Reorder_level, &supply.Low_stock)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &supply, ErrSupplyNotFound
+		}
+		return &supply, err
+	}
+
+	return &supply, nil
+}
+
+// A new supply starts without stock, it is added with a restock
+func (sr *supplyRepository) CreateSupply(supply *entity.Supply) (*entity.Supply, error) {
+	query := "INSERT INTO supply (name,unit,reorder_level) VALUES ($1,$2,$3) RETURNING supply_id,stock,stock <= reorder_level"
+
+	err := sr.DB.QueryRow(query, supply.Name, supply.Unit, supply.Reorder_level).Scan(&supply.Supply_id, &supply.Stock, &supply.Low_stock)
+	if err != nil {
+		return supply, supplyError(err)
+	}
+	return supply, nil
+}
+
+// Stock only changes with stock movements
+func (sr *supplyRepository) UpdateSupply(id int, supply *entity.Supply) (*entity.Supply, error) {
+	query := "UPDATE supply SET name = $2,unit = $3,reorder_level = $4,updated_at = CURRENT_TIMESTAMP WHERE supply_id = $1 RETURNING stock,stock <= reorder_level"
+
+	err := sr.DB.QueryRow(query, id, supply.Name, supply.Unit, supply.Reorder_level).Scan(&supply.Stock, &supply.Low_stock)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return supply, ErrSupplyNotFound
+		}
+		return supply, supplyError(err)
+	}
+	return supply, nil
+}
+
+func (sr *supplyRepository) AdjustStock(movement *entity.Stock_movement) (*entity.Stock_movement, error) {
+	tx, err := sr.DB.Begin()
+	if err != nil {
+		err = fmt.Errorf("failed starting transaction , %s", err)
+		return movement, err
+	}
+
+	err = insertStockMovement(tx, movement)
+	if err != nil {
+		tx.Rollback()
+		return movement, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		err = fmt.Errorf("failed commit transaction , %s", err)
+		return movement, err
+	}
+
+	return movement, nil
+}
+
+// Stock ledger of the supply, the latest first
+func (sr *supplyRepository) GetStockMovements(id int) ([]entity.Stock_movement, error) {
+	movements := []entity.Stock_movement{}
+
+	query := `SELECT sm.stock_movement_id,sm.supply_id,COALESCE(sm.transaction_id::text,''),COALESCE(t.bill_number,''),COALESCE(sm.employee_id::text,''),
+	sm.change,sm.stock_after,sm.movement_type,sm.note,sm.created_at
+	FROM stock_movement AS sm
+	LEFT JOIN transaction AS t ON sm.transaction_id = t.transaction_id
+	WHERE sm.supply_id = $1 ORDER BY sm.created_at DESC,sm.stock_movement_id DESC`
+
+	rows, err := sr.DB.Query(query, id)
+	if err != nil {
+		return movements, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		movement := entity.Stock_movement{}
+		err = rows.Scan(&movement.Stock_movement_id, &movement.Supply_id, &movement.Transaction_id, &movement.Bill_number, &movement.Employee_id,
+			&movement.Change, &movement.Stock_after, &movement.Movement_type, &movement.Note, &movement.Created_at)
+		if err != nil {
+			return movements, err
+		}
+		movements = append(movements, movement)
+	}
+
+	return movements, rows.Err()
+}
+
+func (sr *supplyRepository) GetProductSupplies(productId int) ([]entity.Product_supply, error) {
+	supplies := []entity.Product_supply{}
+
+	query := `SELECT ps.product_id,ps.supply_id,s.name,s.unit,ps.usage_per_unit FROM product_supply AS ps
+	INNER JOIN supply AS s ON ps.supply_id = s.supply_id
+	WHERE ps.product_id = $1 ORDER BY s.name`
+
+	rows, err := sr.DB.Query(query, productId)
+	if err != nil {
+		return supplies, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		supply := entity.Product_supply{}
+		err = rows.Scan(&supply.Product_id, &supply.Supply_id, &supply.Supply_name, &supply.Unit, &supply.Usage_per_unit)
+		if err != nil {
+			return supplies, err
+		}
+		supplies = append(supplies, supply)
+	}
+
+	return supplies, rows.Err()
+}
+
+// Replace the bill of materials of the product, bills already made keep what they took
+func (sr *supplyRepository) SetProductSupplies(productId int, supplies []entity.Product_supply) ([]entity.Product_supply, error) {
+	tx, err := sr.DB.Begin()
+	if err != nil {
+		err = fmt.Errorf("failed starting transaction , %s", err)
+		return supplies, err
+	}
+
+	_, err = tx.Exec("DELETE FROM product_supply WHERE product_id = $1", productId)
+	if err != nil {
+		tx.Rollback()
+		return supplies, err
+	}
+
+	for _, supply := range supplies {
+		_, err = tx.Exec("INSERT INTO product_supply (product_id,supply_id,usage_per_unit) VALUES ($1,$2,$3)", productId, supply.Supply_id, supply.Usage_per_unit)
+		if err != nil {
+			tx.Rollback()
+			return supplies, supplyError(err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		err = fmt.Errorf("failed commit transaction , %s", err)
+		return supplies, err
+	}
+
+	return sr.GetProductSupplies(productId)
+}
+
+// Lock the supply so concurrent movements add up. A bill may take the stock below zero,
+// so the counter is never stopped by a stock count that is off, a movement entered by hand may not
+func insertStockMovement(tx *sql.Tx, movement *entity.Stock_movement) error {
+	err := tx.QueryRow("UPDATE supply SET stock = stock + $2, updated_at = CURRENT_TIMESTAMP WHERE supply_id = $1 RETURNING stock", movement.Supply_id, movement.Change).Scan(&movement.Stock_after)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrSupplyNotFound
+		}
+		return fmt.Errorf("failed update supply stock , %s", err)
+	}
+
+	if movement.Transaction_id == "" && movement.Stock_after < 0 {
+		return fmt.Errorf("%w, stock is %v", ErrStockBelowZero, movement.Stock_after-movement.Change)
+	}
+
+	createMovement := `INSERT INTO stock_movement (supply_id,transaction_id,employee_id,change,stock_after,movement_type,note)
+	VALUES ($1,NULLIF($2,'')::int,NULLIF($3,'')::int,$4,$5,$6,$7) RETURNING stock_movement_id,created_at`
+
+	err = tx.QueryRow(createMovement, movement.Supply_id, movement.Transaction_id, movement.Employee_id, movement.Change, movement.Stock_after, movement.Movement_type, movement.Note).Scan(&movement.Stock_movement_id, &movement.Created_at)
+	if err != nil {
+		return fmt.Errorf("failed insert into stock movement , %s", err)
+	}
+	return nil
+}
+
+// Move the stock a bill needs for its bill details, or all of it back when the bill no longer
+// uses supplies. Only the difference with what the bill took before is moved, so it can run after every change
+func settleStock(tx *sql.Tx, transactionId string, used bool, note string) error {
+	query := `SELECT COALESCE(n.supply_id,t.supply_id),COALESCE(n.qty,0) - COALESCE(t.qty,0) FROM
+	(SELECT ps.supply_id,ROUND(SUM(td.qty * ps.usage_per_unit),3) AS qty FROM transaction_detail AS td
+	INNER JOIN product_supply AS ps ON td.product_id = ps.product_id
+	WHERE td.transaction_id = $1 AND td.removed_at IS NULL AND $2 GROUP BY ps.supply_id) AS n
+	FULL JOIN (SELECT supply_id,-SUM(change) AS qty FROM stock_movement WHERE transaction_id = $1 GROUP BY supply_id) AS t
+	ON n.supply_id = t.supply_id
+	WHERE COALESCE(n.qty,0) <> COALESCE(t.qty,0) ORDER BY 1`
+
+	rows, err := tx.Query(query, transactionId, used)
+	if err != nil {
+		return err
+	}
+
+	movements := []entity.Stock_movement{}
+	for rows.Next() {
+		movement := entity.Stock_movement{Transaction_id: transactionId, Note: note}
+		needed := 0.0
+		err = rows.Scan(&movement.Supply_id, &needed)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		movement.Change = -needed
+		movement.Movement_type = entity.Stock_usage
+		if needed < 0 {
+			movement.Movement_type = entity.Stock_return
+		}
+		movements = append(movements, movement)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	// Supplies are locked in id order so two bills can not deadlock
+	for i := range movements {
+		err = insertStockMovement(tx, &movements[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func supplyError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23505":
+			return ErrSupplyNameTaken
+		case "23503":
+			return ErrSupplyNotFound
+		}
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"submission-project-enigma-laundry/entity"
+	"time"
+)
+
+// Filter of ListTransaction, zero value fields are ignored
+type TransactionFilter struct {
+	StartDate     time.Time
+	EndDate       time.Time
+	ProductName   string
+	Status        string
+	PaymentStatus string
+	CustomerId    int
+	EmployeeId    int
+	MinTotal      *int
+	MaxTotal      *int
+}
+
+// Collects WHERE conditions with their $n placeholder arguments
+type queryBuilder struct {
+	conditions []string
+	args       []interface{}
+	// Put before the requested sort, ex : search relevance
+	orders []string
+}
+
+// Add a condition, every ? in condition is replaced with the next placeholder
+func (qb *queryBuilder) where(condition string, args ...interface{}) {
+	for _, arg := range args {
+		condition = strings.Replace(condition, "?", qb.arg(arg), 1)
+	}
+	qb.conditions = append(qb.conditions, condition)
+}
+
+// Add an argument and return its placeholder
+func (qb *queryBuilder) arg(value interface{}) string {
+	qb.args = append(qb.args, value)
+	return fmt.Sprintf("$%d", len(qb.args))
+}
+
+func (qb *queryBuilder) clause() string {
+	if len(qb.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(qb.conditions, " AND ")
+}
+
+// Escape LIKE wildcards so the value is matched literally
+func likePattern(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(value) + "%"
+}
+
+func buildTransactionQuery(filter TransactionFilter) (string, []interface{}) {
+	qb := newTransactionQueryBuilder(filter)
+	return qb.clause(), qb.args
+}
+
+func newTransactionQueryBuilder(filter TransactionFilter) queryBuilder {
+	qb := queryBuilder{}
+
+	if !filter.StartDate.IsZero() {
+		qb.where("t.entry_date >= ?", filter.StartDate)
+	}
+	if !filter.EndDate.IsZero() {
+		qb.where("t.finish_date <= ?", filter.EndDate)
+	}
+	if filter.ProductName != "" {
+		qb.where(`EXISTS (SELECT 1 FROM transaction_detail AS tdp INNER JOIN product AS p ON tdp.product_id = p.product_id
+			WHERE tdp.transaction_id = t.transaction_id AND tdp.removed_at IS NULL AND p.product_name LIKE ?)`, likePattern(filter.ProductName))
+	}
+	if filter.Status != "" {
+		qb.where("t.status = ?", filter.Status)
+	}
+	switch filter.PaymentStatus {
+	case entity.Payment_status_unpaid:
+		qb.where(paidAmountQuery + " = 0")
+	case entity.Payment_status_partial:
+		qb.where(paidAmountQuery + " > 0 AND " + paidAmountQuery + " < " + totalBillQuery)
+	case entity.Payment_status_paid:
+		qb.where(paidAmountQuery + " > 0 AND " + paidAmountQuery + " >= " + totalBillQuery)
+	}
+	if filter.CustomerId != 0 {
+		qb.where("t.customer_id = ?", filter.CustomerId)
+	}
+	if filter.EmployeeId != 0 {
+		qb.where("t.employee_id = ?", filter.EmployeeId)
+	}
+	if filter.MinTotal != nil {
+		qb.where(totalBillQuery+" >= ?", *filter.MinTotal)
+	}
+	if filter.MaxTotal != nil {
+		qb.where(totalBillQuery+" <= ?", *filter.MaxTotal)
+	}
+
+	return qb
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildTransactionQueryProductNameWithQuote(t *testing.T) {
+	productName := "O'Brien'; DROP TABLE transaction; --"
+
+	query, args := buildTransactionQuery(TransactionFilter{ProductName: productName})
+
+	if !strings.Contains(query, "p.product_name LIKE $1") {
+		t.Fatalf("unexpected query %q", query)
+	}
+	if strings.Contains(query, "'") || strings.Contains(query, "DROP") {
+		t.Fatalf("product name leaked into query %q", query)
+	}
+	if len(args) != 1 || args[0] != "%"+productName+"%" {
+		t.Fatalf("unexpected args %v", args)
+	}
+}
+
+func TestBuildTransactionQueryEscapesLikeWildcard(t *testing.T) {
+	_, args := buildTransactionQuery(TransactionFilter{ProductName: `100%_cotton\`})
+
+	if args[0] != `%100\%\_cotton\\%` {
+		t.Fatalf("wildcard not escaped %v", args[0])
+	}
+}
+
+func TestBuildTransactionQueryPlaceholderOrder(t *testing.T) {
+	startDate := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2024, 10, 31, 0, 0, 0, 0, time.UTC)
+	minTotal, maxTotal := 10000, 50000
+
+	query, args := buildTransactionQuery(TransactionFilter{
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Status:     "ready",
+		CustomerId: 3,
+		EmployeeId: 2,
+		MinTotal:   &minTotal,
+		MaxTotal:   &maxTotal,
+	})
+
+	expected := []string{
+		"t.entry_date >= $1",
+		"t.finish_date <= $2",
+		"t.status = $3",
+		"t.customer_id = $4",
+		"t.employee_id = $5",
+		totalBillQuery + " >= $6",
+		totalBillQuery + " <= $7",
+	}
+	if query != " WHERE "+strings.Join(expected, " AND ") {
+		t.Fatalf("unexpected query %q", query)
+	}
+
+	expectedArgs := []interface{}{startDate, endDate, "ready", 3, 2, 10000, 50000}
+	if len(args) != len(expectedArgs) {
+		t.Fatalf("expected %d args, got %d", len(expectedArgs), len(args))
+	}
+	for i := range expectedArgs {
+		if args[i] != expectedArgs[i] {
+			t.Fatalf("arg %d expected %v, got %v", i, expectedArgs[i], args[i])
+		}
+	}
+}
+
+func TestBuildTransactionQueryWithoutFilter(t *testing.T) {
+	query, args := buildTransactionQuery(TransactionFilter{})
+
+	if query != "" || len(args) != 0 {
+		t.Fatalf("expected empty query, got %q %v", query, args)
+	}
+}
@@ -1,198 +1,883 @@
-package repository
-
-import (
-	"database/sql"
-	"submission-project-enigma-laundry/entity"
-	"fmt"
-	"errors"
-	_ "github.com/lib/pq"
-)
-
-type TransactionRepository interface {
-	CreateTransaction(transaction *entity.Transaction) (*entity.Transaction,error)
-	GetTransaction(transaction *entity.Transaction,id int) (*entity.Transaction,error)
-	ListTransaction(transactionQueryParam string) (*sql.Rows, error)
-	TransactionDetails(transactionDetailQueryParam string) (*sql.Rows, error)
-	IsTransactionExist(id int)(bool, error) 
-	IsTransactionDetailExist(id int) (bool, error)
-}
-
-type transactionRepository struct {
-	DB *sql.DB
-}
-
-func NewTransactionRepo(db *sql.DB) TransactionRepository {
-	return &transactionRepository{DB: db}
-}
-
-func (tr *transactionRepository) CreateTransaction(transaction *entity.Transaction) (*entity.Transaction,error) {
-	tx,err := tr.DB.Begin()
-
-	if err != nil {
-		err = fmt.Errorf("failed starting transaction , %s",err)
-		return transaction, err // Handle error if the query fails
-	}
-	
-	createTransaction := "INSERT INTO transaction (customer_id,employee_id,bill_date,entry_date,finish_date) VALUES ($1,$2,$3,$4,$5) RETURNING transaction_id"
-
-	err = tx.QueryRow(createTransaction, transaction.Customer_id, transaction.Employee_id, transaction.Bill_date, transaction.Entry_date, transaction.Finish_date).Scan(&transaction.Transaction_id)
-	if err != nil {
-		err = fmt.Errorf("failed insert into transaction , %s",err)
-		tx.Rollback()
-		return transaction, err // Handle error if the query fails
-	}
-
-	for i := range transaction.Bill_detail {
-		billDetail := &transaction.Bill_detail[i] // Get pointer to the original element
-	
-		getPrice := "SELECT price FROM product WHERE product_id = $1;"
-		err = tx.QueryRow(getPrice, billDetail.Product_id).Scan(&billDetail.Product_price)
-		if err != nil {
-			err = fmt.Errorf("failed to get price from product, %s", err)
-			tx.Rollback()
-			return transaction, err
-		}
-	
-		createTransactionDetail := "INSERT INTO transaction_detail (transaction_id, product_id, product_price, qty) VALUES ($1, $2, $3, $4) RETURNING transaction_detail_id"
-		err = tx.QueryRow(createTransactionDetail, transaction.Transaction_id, billDetail.Product_id, billDetail.Product_price, billDetail.Qty).Scan(&billDetail.Transaction_detail_id)
-		if err != nil {
-			err = fmt.Errorf("failed to insert into transaction detail, %s", err)
-			tx.Rollback()
-			return transaction, err
-		}
-	
-		billDetail.Transaction_id = transaction.Transaction_id
-	}	
-	
-	err = tx.Commit()
-	if err != nil {
-		err = fmt.Errorf("failed commit transaction , %s",err)
-		return transaction, err // Handle error if the query fails
-	}
-
-	return transaction,nil
-}
-
-func (tr *transactionRepository) IsTransactionExist(id int) (bool, error) {
-	query := "SELECT transaction_id FROM transaction WHERE transaction_id = $1"
-
-	// Execute the query and scan the result
-	err := tr.DB.QueryRow(query, id).Scan(&id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			// No transaction found
-			return false, nil // No error, just return false
-		}
-		// Return any other errors encountered
-		return false, err
-	}
-
-	// transaction exists
-	return true, nil
-}
-
-func (tr *transactionRepository) IsTransactionDetailExist(id int) (bool, error) {
-	query := "SELECT transaction_id FROM transaction_detail WHERE transaction_id = $1"
-
-	// Execute the query and scan the result
-	err := tr.DB.QueryRow(query, id).Scan(&id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			// No transaction Detail found
-			return false, nil // No error, just return false
-		}
-		// Return any other errors encountered
-		return false, err
-	}
-
-	// transaction detail exists
-	return true, nil
-}
-
-func (tr *transactionRepository) GetTransaction(transaction *entity.Transaction,id int) (*entity.Transaction,error) {
-	select_transaction_by_id := `SELECT 
-	t.transaction_id,t.bill_date,t.entry_date,t.finish_date,
-	e.employee_id,e.name,e.phone_number,e.address,
-	c.customer_id,c.name,c.phone_number,c.address
-	FROM transaction AS t 
-	INNER JOIN employee AS e ON t.employee_id = e.employee_id 
-	INNER JOIN customer AS c ON t.customer_id = c.customer_id 
-	WHERE t.transaction_id = $1;`
-
-	err := tr.DB.QueryRow(select_transaction_by_id,id).Scan(&transaction.Transaction_id,&transaction.Bill_date,&transaction.Entry_date,&transaction.Finish_date,&transaction.Employee.Employee_id,&transaction.Employee.Name,&transaction.Employee.Phone_number,&transaction.Employee.Address,&transaction.Customer.Customer_id,&transaction.Customer.Name,&transaction.Customer.Phone_number,&transaction.Customer.Address)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			err = errors.New("transaction not found")
-			return transaction, err
-		}
-
-		return transaction, err
-	}
-
-	select_transaction_detail_by_transaction_id := `SELECT 
-	td.transaction_detail_id,td.transaction_id,td.product_price,td.qty,
-	p.product_id,p.product_name,p.price,p.unit
-	FROM transaction_detail AS td
-	INNER JOIN product AS p ON td.product_id = p.product_id WHERE transaction_id = $1;`
-
-	rows, err := tr.DB.Query(select_transaction_detail_by_transaction_id,id)
-	if err != nil {
-		return transaction, err
-	}
-	
-	defer rows.Close()
-
-	for rows.Next() {
-		transaction_detail := entity.Transaction_detail{}
-		err = rows.Scan(&transaction_detail.Transaction_detail_id,&transaction_detail.Transaction_id,&transaction_detail.Product_price,&transaction_detail.Qty,&transaction_detail.Product.Product_id,&transaction_detail.Product.Product_name,&transaction_detail.Product.Price,&transaction_detail.Product.Unit)
-		if err != nil {
-			return transaction, err
-		}
-		transaction.Total_bill += transaction_detail.Product_price * transaction_detail.Qty
-		transaction.Bill_detail = append(transaction.Bill_detail, transaction_detail)
-	}
-
-	return transaction, nil
-}
-
-func (tr *transactionRepository)ListTransaction(transactionQueryParam string) (*sql.Rows, error) {
-	query := `
-		SELECT t.transaction_id, t.bill_date, t.entry_date, t.finish_date, e.employee_id, e.name, e.phone_number, e.address,
-		       c.customer_id, c.name, c.phone_number, c.address
-		FROM transaction AS t
-		INNER JOIN employee AS e ON t.employee_id = e.employee_id
-		INNER JOIN customer AS c ON t.customer_id = c.customer_id
-		INNER JOIN transaction_detail AS td ON t.transaction_id = td.transaction_id
-		INNER JOIN product AS p ON td.product_id = p.product_id`
-
-	if transactionQueryParam != ""{
-		query += transactionQueryParam
-	}
-
-	rows, err := tr.DB.Query(query)
-	if err != nil {
-		return nil, err
-	}
-
-	return rows, nil
-}
-
-func (tr *transactionRepository) TransactionDetails(transactionDetailQueryParam string) (*sql.Rows, error) {
-	query := `SELECT 
-	td.transaction_detail_id,td.transaction_id,td.product_price,td.qty,
-	p.product_id,p.product_name,p.price,p.unit
-	FROM transaction_detail AS td
-	INNER JOIN product AS p ON td.product_id = p.product_id`
-
-	if transactionDetailQueryParam != "" {
-		query += transactionDetailQueryParam
-	}
-
-	rows,err := tr.DB.Query(query)
-	if err != nil {
-		return nil,err
-	}
-
-	return rows,nil
-}
-
+package repository
+
+import (
+	"database/sql"
+	"submission-project-enigma-laundry/entity"
+	"fmt"
+	"errors"
+	"strconv"
+	"time"
+	"github.com/lib/pq"
+)
+
+type TransactionRepository interface {
+	CreateTransaction(transaction *entity.Transaction) (*entity.Transaction,error)
+	GetTransaction(transaction *entity.Transaction,id int) (*entity.Transaction,error)
+	ListTransaction(filter TransactionFilter, page entity.Page_request) ([]entity.Transaction, int, error)
+	IsTransactionExist(id int)(bool, error) 
+	GetTransactionIdByBillNumber(billNumber string) (int, bool, error)
+	IsTransactionDetailExist(id int) (bool, error)
+	UpdateTransactionStatus(id int, status string, note string) (*entity.Transaction_status_history, error)
+	GetStatusHistory(id int) ([]entity.Transaction_status_history, error)
+	UpdateTransaction(id int, transaction *entity.Transaction) (*entity.Transaction, error)
+	CancelTransaction(id int, reason string) (*entity.Transaction_status_history, error)
+}
+
+// Returned when the requested status can not be reached from the current status
+var ErrInvalidStatusTransition = errors.New("invalid status transition")
+
+// Returned when editing a bill that is already picked up or cancelled
+var ErrTransactionNotEditable = errors.New("transaction can not be edited")
+
+// Returned when an edited bill total is lower than the amount already paid
+var ErrTotalBelowPaid = errors.New("bill total is lower than the paid amount")
+
+// Returned when an edited bill detail does not belong to the bill
+var ErrBillDetailNotFound = errors.New("bill detail not found")
+
+// Promo code used by transaction t, empty when none
+const promoCodeQuery = `COALESCE((SELECT pr.code FROM promo AS pr WHERE pr.promo_id = t.promo_id),'')`
+
+// Prefix of every bill number, ex : EL-JKT-20261018-0042
+const billNumberPrefix = "EL"
+
+type transactionRepository struct {
+	DB *sql.DB
+	branchCode string
+	taxRate int
+	stockOnFinish bool
+}
+
+func NewTransactionRepo(db *sql.DB, branchCode string, taxRate int, stockOnFinish bool) TransactionRepository {
+	return &transactionRepository{DB: db, branchCode: branchCode, taxRate: taxRate, stockOnFinish: stockOnFinish}
+}
+
+// Whether a bill with the status has taken its supplies from stock
+func (tr *transactionRepository) usesStock(status string) bool {
+	if status == entity.Status_cancelled {
+		return false
+	}
+	if tr.stockOnFinish {
+		return status == entity.Status_ready || status == entity.Status_picked_up
+	}
+	return true
+}
+
+func (tr *transactionRepository) CreateTransaction(transaction *entity.Transaction) (*entity.Transaction,error) {
+	tx,err := tr.DB.Begin()
+
+	if err != nil {
+		err = fmt.Errorf("failed starting transaction , %s",err)
+		return transaction, err // Handle error if the query fails
+	}
+	
+	transaction.Status = entity.Status_received
+	transaction.Tax_rate = tr.taxRate
+
+	transaction.Bill_number, err = nextBillNumber(tx, tr.branchCode, transaction.Bill_date)
+	if err != nil {
+		tx.Rollback()
+		return transaction, err
+	}
+
+	// Price every bill detail first, the surcharge is taken from their subtotal
+	for i := range transaction.Bill_detail {
+		err = priceBillDetail(tx, &transaction.Bill_detail[i], transaction.Bill_date)
+		if err != nil {
+			tx.Rollback()
+			return transaction, err
+		}
+		if !transaction.Bill_detail[i].Product.Active {
+			tx.Rollback()
+			return transaction, fmt.Errorf("%w, %s can not be put on a bill", entity.ErrProductInactive, transaction.Bill_detail[i].Product.Product_name)
+		}
+		err = useQuota(tx, transaction.Customer_id, transaction.Entry_date, &transaction.Bill_detail[i])
+		if err != nil {
+			tx.Rollback()
+			return transaction, err
+		}
+	}
+	calculateBill(transaction)
+	transaction.Surcharge = entity.GetSurcharge(transaction.Surcharge_type, transaction.Surcharge_value, transaction.Subtotal)
+
+	if transaction.Promo_code != "" {
+		err = applyPromo(tx, transaction)
+		if err != nil {
+			tx.Rollback()
+			return transaction, err
+		}
+	}
+
+	err = applyLoyalty(tx, transaction)
+	if err != nil {
+		tx.Rollback()
+		return transaction, err
+	}
+	calculateBill(transaction)
+
+	createTransaction := `INSERT INTO transaction (bill_number,customer_id,employee_id,bill_date,entry_date,finish_date,status,service_tier,surcharge_type,surcharge_value,surcharge,promo_id,discount,
+	member_tier,member_discount_percent,member_discount,points_redeemed,points_discount,subtotal,tax_rate,tax,total_bill)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,NULLIF($12,'')::int,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22) RETURNING transaction_id`
+
+	err = tx.QueryRow(createTransaction, transaction.Bill_number, transaction.Customer_id, transaction.Employee_id, transaction.Bill_date, transaction.Entry_date, transaction.Finish_date, transaction.Status, transaction.Service_tier, transaction.Surcharge_type, transaction.Surcharge_value, transaction.Surcharge, transaction.Promo_id, transaction.Discount,
+		transaction.Member_tier, transaction.Member_discount_percent, transaction.Member_discount, transaction.Points_redeemed, transaction.Points_discount, transaction.Subtotal, transaction.Tax_rate, transaction.Tax, transaction.Total_bill).Scan(&transaction.Transaction_id)
+	if err != nil {
+		err = fmt.Errorf("failed insert into transaction , %s",err)
+		tx.Rollback()
+		return transaction, err // Handle error if the query fails
+	}
+
+	createHistory := "INSERT INTO transaction_status_history (transaction_id,to_status,note) VALUES ($1,$2,$3)"
+	_, err = tx.Exec(createHistory, transaction.Transaction_id, transaction.Status, "bill created")
+	if err != nil {
+		err = fmt.Errorf("failed insert into transaction status history , %s",err)
+		tx.Rollback()
+		return transaction, err
+	}
+
+	for i := range transaction.Bill_detail {
+		billDetail := &transaction.Bill_detail[i] // Get pointer to the original element
+	
+		createTransactionDetail := "INSERT INTO transaction_detail (transaction_id, product_id, product_price, qty, line_total, tax_inclusive, subscription_id, quota_used) VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7,'')::int, $8) RETURNING transaction_detail_id"
+		err = tx.QueryRow(createTransactionDetail, transaction.Transaction_id, billDetail.Product_id, billDetail.Product_price, billDetail.Qty, billDetail.Line_total, billDetail.Tax_inclusive, billDetail.Subscription_id, billDetail.Quota_used).Scan(&billDetail.Transaction_detail_id)
+		if err != nil {
+			err = fmt.Errorf("failed to insert into transaction detail, %s", err)
+			tx.Rollback()
+			return transaction, err
+		}
+	
+		billDetail.Transaction_id = transaction.Transaction_id
+	}	
+
+	err = settleStock(tx, transaction.Transaction_id, tr.usesStock(transaction.Status), "bill created")
+	if err != nil {
+		tx.Rollback()
+		return transaction, err
+	}
+
+	if transaction.Points_redeemed > 0 {
+		err = insertPointEntry(tx, &entity.Point_entry{Customer_id: transaction.Customer_id, Transaction_id: transaction.Transaction_id, Points: -transaction.Points_redeemed, Entry_type: entity.Point_redeem, Note: "redeemed on bill " + transaction.Bill_number})
+		if err != nil {
+			tx.Rollback()
+			return transaction, err
+		}
+	}
+
+	// Deposit paid at drop-off
+	if transaction.Deposit != nil {
+		if transaction.Deposit.Amount > transaction.Total_bill {
+			tx.Rollback()
+			err = fmt.Errorf("%w, outstanding is %d", ErrPaymentExceedsOutstanding, transaction.Total_bill)
+			return transaction, err
+		}
+
+		transaction.Deposit.Transaction_id = transaction.Transaction_id
+		transaction.Deposit.Payment_type = entity.Payment_type_deposit
+		err = insertPayment(tx, transaction.Customer_id, transaction.Deposit)
+		if err != nil {
+			tx.Rollback()
+			return transaction, err
+		}
+		transaction.Paid_amount = transaction.Deposit.Amount
+	}
+	calculateOutstanding(transaction)
+
+	if transaction.Total_bill > 0 && transaction.Outstanding == 0 {
+		err = earnPoints(tx, transaction.Transaction_id, transaction.Customer_id, transaction.Total_bill)
+		if err != nil {
+			tx.Rollback()
+			return transaction, err
+		}
+	}
+	
+	err = tx.Commit()
+	if err != nil {
+		err = fmt.Errorf("failed commit transaction , %s",err)
+		return transaction, err // Handle error if the query fails
+	}
+
+	return transaction,nil
+}
+
+func (tr *transactionRepository) IsTransactionExist(id int) (bool, error) {
+	query := "SELECT transaction_id FROM transaction WHERE transaction_id = $1"
+
+	// Execute the query and scan the result
+	err := tr.DB.QueryRow(query, id).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// No transaction found
+			return false, nil // No error, just return false
+		}
+		// Return any other errors encountered
+		return false, err
+	}
+
+	// transaction exists
+	return true, nil
+}
+
+// Next number of the branch for the bill day. The sequence row stays locked until the
+// bill is committed, a rolled back bill gives its number back so numbers have no gap
+func nextBillNumber(tx *sql.Tx, branchCode string, billDate time.Time) (string, error) {
+	nextNumber := `INSERT INTO bill_sequence (branch_code,bill_day,last_number) VALUES ($1,$2,1)
+	ON CONFLICT (branch_code,bill_day) DO UPDATE SET last_number = bill_sequence.last_number + 1
+	RETURNING last_number`
+
+	number := 0
+	err := tx.QueryRow(nextNumber, branchCode, billDate.Format(time.DateOnly)).Scan(&number)
+	if err != nil {
+		err = fmt.Errorf("failed to get bill number , %s", err)
+		return "", err
+	}
+
+	return formatBillNumber(branchCode, billDate, number), nil
+}
+
+// Ex : EL-JKT-20241001-0042, the number is padded to 4 digits and grows past 9999
+func formatBillNumber(branchCode string, billDate time.Time, number int) string {
+	return fmt.Sprintf("%s-%s-%s-%04d", billNumberPrefix, branchCode, billDate.Format("20060102"), number)
+}
+
+// Member discount of the customer tier and the value of the redeemed points
+func applyLoyalty(tx *sql.Tx, transaction *entity.Transaction) error {
+	balance, lifetime, err := lockCustomerPoints(tx, transaction.Customer_id)
+	if err != nil {
+		return err
+	}
+
+	tier := entity.GetMembershipTier(lifetime)
+	transaction.Member_tier = tier.Code
+	transaction.Member_discount_percent = tier.Discount_percent
+	transaction.Member_discount = entity.GetMemberDiscount(tier.Discount_percent, transaction.Subtotal-transaction.Discount)
+
+	if transaction.Points_redeemed > balance {
+		return fmt.Errorf("%w, balance is %d", ErrNotEnoughPoints, balance)
+	}
+	transaction.Points_discount = transaction.Points_redeemed * entity.Point_value
+	if transaction.Points_discount > transaction.AmountBeforeTax() {
+		return fmt.Errorf("%w, at most %d points can be redeemed", ErrPointsExceedBill, (transaction.AmountBeforeTax()+transaction.Points_discount)/entity.Point_value)
+	}
+	return nil
+}
+
+// Promo is locked until the bill is saved so two bills of one customer can not pass the usage limit together
+func applyPromo(tx *sql.Tx, transaction *entity.Transaction) error {
+	promo := entity.Promo{}
+	err := scanPromo(tx.QueryRow(selectPromoQuery+" WHERE pr.code = $1 FOR UPDATE OF pr", transaction.Promo_code), &promo)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w, code %s", ErrPromoNotFound, transaction.Promo_code)
+		}
+		return err
+	}
+
+	if !promo.IsActiveOn(transaction.Bill_date) {
+		return fmt.Errorf("%w, promo is only valid from %s until %s on the bill date", entity.ErrPromoNotApplicable, promo.Starts_at.Format(time.DateTime), promo.Ends_at.Format(time.DateTime))
+	}
+
+	if promo.Usage_limit > 0 {
+		used := 0
+		countUsage := "SELECT COUNT(*) FROM transaction WHERE promo_id = $1 AND customer_id = $2 AND status <> $3"
+		err = tx.QueryRow(countUsage, promo.Promo_id, transaction.Customer_id, entity.Status_cancelled).Scan(&used)
+		if err != nil {
+			return err
+		}
+		if used >= promo.Usage_limit {
+			return fmt.Errorf("%w, customer already used it %d times", entity.ErrPromoNotApplicable, used)
+		}
+	}
+
+	transaction.Discount, err = promo.GetDiscount(transaction.Bill_detail)
+	if err != nil {
+		return err
+	}
+	transaction.Promo_id = promo.Promo_id
+	transaction.Promo_code = promo.Code
+	return nil
+}
+
+// Read the price effective on the bill date and the pricing model of the product and charge the bill detail with it
+func priceBillDetail(tx *sql.Tx, billDetail *entity.Transaction_detail, billDate time.Time) error {
+	getPrice := "SELECT product_id,product_name," + priceOnQuery("product", "$2::date") + ",unit,pricing_model,min_weight,tax_inclusive,product_type,active FROM product WHERE product_id = $1;"
+	product := &billDetail.Product
+	err := tx.QueryRow(getPrice, billDetail.Product_id, billDate).Scan(&product.Product_id, &product.Product_name, &product.Price, &product.Unit, &product.Pricing_model, &product.Min_weight, &product.Tax_inclusive, &product.Product_type, &product.Active)
+	if err != nil {
+		err = fmt.Errorf("failed to get price from product, %s", err)
+		return err
+	}
+	if product.Product_type == entity.Product_type_package {
+		return fmt.Errorf("%w, subscribe the customer to %s instead", entity.ErrPackageOnBill, product.Product_name)
+	}
+
+	billDetail.Product_price = product.Price
+	billDetail.Tax_inclusive = product.Tax_inclusive
+	billDetail.Line_total, err = entity.GetLineTotal(*product, billDetail.Qty)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tr *transactionRepository) GetTransactionIdByBillNumber(billNumber string) (int, bool, error) {
+	query := "SELECT transaction_id FROM transaction WHERE bill_number = $1"
+
+	id := 0
+	err := tr.DB.QueryRow(query, billNumber).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return id, false, nil
+		}
+		return id, false, err
+	}
+
+	return id, true, nil
+}
+
+func (tr *transactionRepository) IsTransactionDetailExist(id int) (bool, error) {
+	query := "SELECT transaction_id FROM transaction_detail WHERE transaction_id = $1 AND removed_at IS NULL"
+
+	// Execute the query and scan the result
+	err := tr.DB.QueryRow(query, id).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// No transaction Detail found
+			return false, nil // No error, just return false
+		}
+		// Return any other errors encountered
+		return false, err
+	}
+
+	// transaction detail exists
+	return true, nil
+}
+
+func (tr *transactionRepository) GetTransaction(transaction *entity.Transaction,id int) (*entity.Transaction,error) {
+	select_transaction_by_id := `SELECT 
+	t.transaction_id,t.bill_number,t.bill_date,t.entry_date,t.finish_date,t.status,t.cancel_reason,t.cancelled_at,
+	t.service_tier,t.surcharge_type,t.surcharge_value,t.surcharge,` + promoCodeQuery + `,t.discount,
+	t.member_tier,t.member_discount_percent,t.member_discount,t.points_redeemed,t.points_discount,t.subtotal,t.tax_rate,t.tax,t.total_bill,
+	e.employee_id,e.name,e.phone_number,e.address,
+	c.customer_id,c.name,c.phone_number,c.address
+	FROM transaction AS t 
+	INNER JOIN employee AS e ON t.employee_id = e.employee_id 
+	INNER JOIN customer AS c ON t.customer_id = c.customer_id 
+	WHERE t.transaction_id = $1;`
+
+	var cancelledAt sql.NullTime
+	err := tr.DB.QueryRow(select_transaction_by_id,id).Scan(&transaction.Transaction_id,&transaction.Bill_number,&transaction.Bill_date,&transaction.Entry_date,&transaction.Finish_date,&transaction.Status,&transaction.Cancel_reason,&cancelledAt,&transaction.Service_tier,&transaction.Surcharge_type,&transaction.Surcharge_value,&transaction.Surcharge,&transaction.Promo_code,&transaction.Discount,&transaction.Member_tier,&transaction.Member_discount_percent,&transaction.Member_discount,&transaction.Points_redeemed,&transaction.Points_discount,&transaction.Subtotal,&transaction.Tax_rate,&transaction.Tax,&transaction.Total_bill,&transaction.Employee.Employee_id,&transaction.Employee.Name,&transaction.Employee.Phone_number,&transaction.Employee.Address,&transaction.Customer.Customer_id,&transaction.Customer.Name,&transaction.Customer.Phone_number,&transaction.Customer.Address)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = errors.New("transaction not found")
+			return transaction, err
+		}
+
+		return transaction, err
+	}
+	if cancelledAt.Valid {
+		transaction.Cancelled_at = &cancelledAt.Time
+	}
+
+	select_transaction_detail_by_transaction_id := `SELECT 
+	td.transaction_detail_id,td.transaction_id,td.product_price,td.qty,td.line_total,td.tax_inclusive,COALESCE(td.subscription_id::text,''),td.quota_used,
+	p.product_id,p.product_name,` + priceOnQuery("p", "CURRENT_DATE") + `,p.unit,p.pricing_model,p.min_weight,p.tax_inclusive,p.product_type
+	FROM transaction_detail AS td
+	INNER JOIN product AS p ON td.product_id = p.product_id WHERE transaction_id = $1 AND td.removed_at IS NULL;`
+
+	rows, err := tr.DB.Query(select_transaction_detail_by_transaction_id,id)
+	if err != nil {
+		return transaction, err
+	}
+	
+	defer rows.Close()
+
+	for rows.Next() {
+		transaction_detail := entity.Transaction_detail{}
+		err = rows.Scan(&transaction_detail.Transaction_detail_id,&transaction_detail.Transaction_id,&transaction_detail.Product_price,&transaction_detail.Qty,&transaction_detail.Line_total,&transaction_detail.Tax_inclusive,&transaction_detail.Subscription_id,&transaction_detail.Quota_used,&transaction_detail.Product.Product_id,&transaction_detail.Product.Product_name,&transaction_detail.Product.Price,&transaction_detail.Product.Unit,&transaction_detail.Product.Pricing_model,&transaction_detail.Product.Min_weight,&transaction_detail.Product.Tax_inclusive,&transaction_detail.Product.Product_type)
+		if err != nil {
+			return transaction, err
+		}
+		transaction.Bill_detail = append(transaction.Bill_detail, transaction_detail)
+	}
+
+	transaction.Status_history, err = tr.GetStatusHistory(id)
+	if err != nil {
+		return transaction, err
+	}
+
+	transaction.Payments, err = selectPayments(tr.DB, id)
+	if err != nil {
+		return transaction, err
+	}
+
+	for _, payment := range transaction.Payments {
+		transaction.Paid_amount += payment.Amount
+	}
+	calculateOutstanding(transaction)
+
+	return transaction, nil
+}
+
+func (tr *transactionRepository) UpdateTransactionStatus(id int, status string, note string) (*entity.Transaction_status_history, error) {
+	history := entity.Transaction_status_history{To_status: status, Note: note}
+
+	tx, err := tr.DB.Begin()
+	if err != nil {
+		err = fmt.Errorf("failed starting transaction , %s", err)
+		return &history, err
+	}
+
+	// Lock the bill so two status changes can not race each other
+	customerId := ""
+	selectStatus := "SELECT transaction_id,customer_id,status FROM transaction WHERE transaction_id = $1 FOR UPDATE"
+	err = tx.QueryRow(selectStatus, id).Scan(&history.Transaction_id, &customerId, &history.From_status)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			err = errors.New("transaction not found")
+			return &history, err
+		}
+		return &history, err
+	}
+
+	if !entity.CanChangeStatus(history.From_status, status) {
+		tx.Rollback()
+		err = fmt.Errorf("%w from %s to %s", ErrInvalidStatusTransition, history.From_status, status)
+		return &history, err
+	}
+
+	updateStatus := "UPDATE transaction SET status = $2, updated_at = CURRENT_TIMESTAMP WHERE transaction_id = $1"
+	if status == entity.Status_cancelled {
+		// The bill is voided, its rows stay for accounting
+		updateStatus = "UPDATE transaction SET status = $2, cancel_reason = $3, cancelled_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE transaction_id = $1"
+	}
+	if status == entity.Status_cancelled {
+		_, err = tx.Exec(updateStatus, id, status, note)
+	} else {
+		_, err = tx.Exec(updateStatus, id, status)
+	}
+	if err != nil {
+		err = fmt.Errorf("failed update transaction status , %s", err)
+		tx.Rollback()
+		return &history, err
+	}
+
+	if status == entity.Status_cancelled {
+		err = reverseBillPoints(tx, history.Transaction_id, customerId)
+		if err != nil {
+			tx.Rollback()
+			return &history, err
+		}
+
+		err = refundWallet(tx, history.Transaction_id, customerId)
+		if err != nil {
+			tx.Rollback()
+			return &history, err
+		}
+
+		err = releaseQuota(tx, history.Transaction_id)
+		if err != nil {
+			tx.Rollback()
+			return &history, err
+		}
+	}
+
+	// Supplies of a bill cancelled after washing started are used up, so they are not given back
+	if status != entity.Status_cancelled || history.From_status == entity.Status_received {
+		err = settleStock(tx, history.Transaction_id, tr.usesStock(status), "bill "+status)
+		if err != nil {
+			tx.Rollback()
+			return &history, err
+		}
+	}
+
+	createHistory := "INSERT INTO transaction_status_history (transaction_id,from_status,to_status,note) VALUES ($1,$2,$3,$4) RETURNING history_id,changed_at"
+	err = tx.QueryRow(createHistory, id, history.From_status, status, note).Scan(&history.History_id, &history.Changed_at)
+	if err != nil {
+		err = fmt.Errorf("failed insert into transaction status history , %s", err)
+		tx.Rollback()
+		return &history, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		err = fmt.Errorf("failed commit transaction , %s", err)
+		return &history, err
+	}
+
+	return &history, nil
+}
+
+func (tr *transactionRepository) CancelTransaction(id int, reason string) (*entity.Transaction_status_history, error) {
+	return tr.UpdateTransactionStatus(id, entity.Status_cancelled, reason)
+}
+
+func (tr *transactionRepository) UpdateTransaction(id int, transaction *entity.Transaction) (*entity.Transaction, error) {
+	tx, err := tr.DB.Begin()
+	if err != nil {
+		err = fmt.Errorf("failed starting transaction , %s", err)
+		return transaction, err
+	}
+
+	// Lock the bill so payments and status changes wait until the edit is done
+	// Edited bills keep the tax rate they were made with
+	selectBill := `SELECT t.transaction_id,t.customer_id,t.bill_number,t.bill_date,t.entry_date,t.status,t.service_tier,t.surcharge_type,t.surcharge_value,COALESCE(t.promo_id::text,''),
+	t.member_tier,t.member_discount_percent,t.points_redeemed,t.points_discount,t.tax_rate,` + paidAmountQuery + ` FROM transaction AS t WHERE t.transaction_id = $1 FOR UPDATE`
+	err = tx.QueryRow(selectBill, id).Scan(&transaction.Transaction_id, &transaction.Customer_id, &transaction.Bill_number, &transaction.Bill_date, &transaction.Entry_date, &transaction.Status, &transaction.Service_tier, &transaction.Surcharge_type, &transaction.Surcharge_value, &transaction.Promo_id,
+		&transaction.Member_tier, &transaction.Member_discount_percent, &transaction.Points_redeemed, &transaction.Points_discount, &transaction.Tax_rate, &transaction.Paid_amount)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			err = errors.New("transaction not found")
+			return transaction, err
+		}
+		return transaction, err
+	}
+
+	if transaction.Status == entity.Status_picked_up || transaction.Status == entity.Status_cancelled {
+		tx.Rollback()
+		err = fmt.Errorf("%w, status is %s", ErrTransactionNotEditable, transaction.Status)
+		return transaction, err
+	}
+
+	// Existing bill details with the price they were billed with, the ones missing from the request are removed
+	existingDetails := map[string]entity.Transaction_detail{}
+	keptDetails := map[string]bool{}
+	rows, err := tx.Query("SELECT transaction_detail_id,product_id,product_price,tax_inclusive FROM transaction_detail WHERE transaction_id = $1 AND removed_at IS NULL", id)
+	if err != nil {
+		tx.Rollback()
+		return transaction, err
+	}
+	for rows.Next() {
+		existing := entity.Transaction_detail{}
+		err = rows.Scan(&existing.Transaction_detail_id, &existing.Product_id, &existing.Product_price, &existing.Tax_inclusive)
+		if err != nil {
+			rows.Close()
+			tx.Rollback()
+			return transaction, err
+		}
+		existingDetails[existing.Transaction_detail_id] = existing
+	}
+	rows.Close()
+
+	// Quota is taken again for the edited bill details
+	err = releaseQuota(tx, transaction.Transaction_id)
+	if err != nil {
+		tx.Rollback()
+		return transaction, err
+	}
+
+	for i := range transaction.Bill_detail {
+		billDetail := &transaction.Bill_detail[i]
+
+		existing, isExisting := existingDetails[billDetail.Transaction_detail_id]
+		if billDetail.Transaction_detail_id != "" && !isExisting {
+			tx.Rollback()
+			err = fmt.Errorf("%w, id %s", ErrBillDetailNotFound, billDetail.Transaction_detail_id)
+			return transaction, err
+		}
+
+		err = priceBillDetail(tx, billDetail, transaction.Bill_date)
+		if err != nil {
+			tx.Rollback()
+			return transaction, err
+		}
+		// A kept line keeps the price it was billed with, only a changed product is priced again
+		if isExisting && existing.Product_id == billDetail.Product_id {
+			billDetail.Product.Price = existing.Product_price
+			billDetail.Product.Tax_inclusive = existing.Tax_inclusive
+			billDetail.Product_price = existing.Product_price
+			billDetail.Tax_inclusive = existing.Tax_inclusive
+			billDetail.Line_total, err = entity.GetLineTotal(billDetail.Product, billDetail.Qty)
+			if err != nil {
+				tx.Rollback()
+				return transaction, err
+			}
+		} else if !billDetail.Product.Active {
+			// Only a new line or a changed product is refused, a kept line stays on the bill
+			tx.Rollback()
+			return transaction, fmt.Errorf("%w, %s can not be put on a bill", entity.ErrProductInactive, billDetail.Product.Product_name)
+		}
+		err = useQuota(tx, transaction.Customer_id, transaction.Entry_date, billDetail)
+		if err != nil {
+			tx.Rollback()
+			return transaction, err
+		}
+
+		if billDetail.Transaction_detail_id == "" {
+			createTransactionDetail := "INSERT INTO transaction_detail (transaction_id, product_id, product_price, qty, line_total, tax_inclusive, subscription_id, quota_used) VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7,'')::int, $8) RETURNING transaction_detail_id"
+			err = tx.QueryRow(createTransactionDetail, id, billDetail.Product_id, billDetail.Product_price, billDetail.Qty, billDetail.Line_total, billDetail.Tax_inclusive, billDetail.Subscription_id, billDetail.Quota_used).Scan(&billDetail.Transaction_detail_id)
+			if err != nil {
+				err = fmt.Errorf("failed to insert into transaction detail, %s", err)
+				tx.Rollback()
+				return transaction, err
+			}
+		} else {
+			keptDetails[billDetail.Transaction_detail_id] = true
+
+			updateTransactionDetail := "UPDATE transaction_detail SET product_id = $2, product_price = $3, qty = $4, line_total = $5, tax_inclusive = $6, subscription_id = NULLIF($7,'')::int, quota_used = $8 WHERE transaction_detail_id = $1"
+			_, err = tx.Exec(updateTransactionDetail, billDetail.Transaction_detail_id, billDetail.Product_id, billDetail.Product_price, billDetail.Qty, billDetail.Line_total, billDetail.Tax_inclusive, billDetail.Subscription_id, billDetail.Quota_used)
+			if err != nil {
+				err = fmt.Errorf("failed to update transaction detail, %s", err)
+				tx.Rollback()
+				return transaction, err
+			}
+		}
+
+		billDetail.Transaction_id = transaction.Transaction_id
+	}
+
+	// Removed lines are voided, not deleted, so the bill history stays for accounting
+	for detailId := range existingDetails {
+		if keptDetails[detailId] {
+			continue
+		}
+		_, err = tx.Exec("UPDATE transaction_detail SET removed_at = CURRENT_TIMESTAMP WHERE transaction_detail_id = $1", detailId)
+		if err != nil {
+			err = fmt.Errorf("failed to remove transaction detail, %s", err)
+			tx.Rollback()
+			return transaction, err
+		}
+	}
+
+	err = settleStock(tx, transaction.Transaction_id, tr.usesStock(transaction.Status), "bill edited")
+	if err != nil {
+		tx.Rollback()
+		return transaction, err
+	}
+
+	// Percent surcharge follows the new subtotal
+	calculateBill(transaction)
+	transaction.Surcharge = entity.GetSurcharge(transaction.Surcharge_type, transaction.Surcharge_value, transaction.Subtotal)
+
+	// Discount follows the new bill details, it is dropped when the promo terms are no longer met
+	if transaction.Promo_id != "" {
+		promo := entity.Promo{}
+		err = scanPromo(tx.QueryRow(selectPromoQuery+" WHERE pr.promo_id = $1", transaction.Promo_id), &promo)
+		if err != nil {
+			tx.Rollback()
+			return transaction, err
+		}
+		transaction.Promo_code = promo.Code
+		transaction.Discount, err = promo.GetDiscount(transaction.Bill_detail)
+		if err != nil && !errors.Is(err, entity.ErrPromoNotApplicable) {
+			tx.Rollback()
+			return transaction, err
+		}
+	}
+
+	// Member discount keeps the tier of the bill, points worth more than the edited bill are given back
+	transaction.Member_discount = entity.GetMemberDiscount(transaction.Member_discount_percent, transaction.Subtotal-transaction.Discount)
+	transaction.Points_discount = transaction.Points_redeemed * entity.Point_value
+	if transaction.Points_discount > transaction.AmountBeforeTax() {
+		usable := (transaction.AmountBeforeTax() + transaction.Points_discount) / entity.Point_value
+		err = insertPointEntry(tx, &entity.Point_entry{Customer_id: transaction.Customer_id, Transaction_id: transaction.Transaction_id, Points: transaction.Points_redeemed - usable, Entry_type: entity.Point_refund, Note: "bill " + transaction.Bill_number + " edited"})
+		if err != nil {
+			tx.Rollback()
+			return transaction, err
+		}
+		transaction.Points_redeemed = usable
+		transaction.Points_discount = usable * entity.Point_value
+	}
+	calculateBill(transaction)
+
+	if transaction.Total_bill < transaction.Paid_amount {
+		tx.Rollback()
+		err = fmt.Errorf("%w, paid amount is %d", ErrTotalBelowPaid, transaction.Paid_amount)
+		return transaction, err
+	}
+
+	updateBill := `UPDATE transaction SET surcharge = $2, discount = $3, member_discount = $4, points_redeemed = $5, points_discount = $6, subtotal = $7, tax = $8, total_bill = $9, updated_at = CURRENT_TIMESTAMP
+	WHERE transaction_id = $1`
+	_, err = tx.Exec(updateBill, id, transaction.Surcharge, transaction.Discount, transaction.Member_discount, transaction.Points_redeemed, transaction.Points_discount, transaction.Subtotal, transaction.Tax, transaction.Total_bill)
+	if err != nil {
+		err = fmt.Errorf("failed update transaction , %s", err)
+		tx.Rollback()
+		return transaction, err
+	}
+
+	// Editing the bill down to the paid amount pays it off
+	if transaction.Total_bill > 0 && transaction.Outstanding == 0 {
+		err = earnPoints(tx, transaction.Transaction_id, transaction.Customer_id, transaction.Total_bill)
+		if err != nil {
+			tx.Rollback()
+			return transaction, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		err = fmt.Errorf("failed commit transaction , %s", err)
+		return transaction, err
+	}
+
+	return transaction, nil
+}
+
+func (tr *transactionRepository) GetStatusHistory(id int) ([]entity.Transaction_status_history, error) {
+	histories := []entity.Transaction_status_history{}
+
+	query := `SELECT history_id,transaction_id,COALESCE(from_status,''),to_status,note,changed_at
+	FROM transaction_status_history WHERE transaction_id = $1 ORDER BY changed_at,history_id`
+
+	rows, err := tr.DB.Query(query, id)
+	if err != nil {
+		return histories, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		history := entity.Transaction_status_history{}
+		err = rows.Scan(&history.History_id, &history.Transaction_id, &history.From_status, &history.To_status, &history.Note, &history.Changed_at)
+		if err != nil {
+			return histories, err
+		}
+		histories = append(histories, history)
+	}
+
+	return histories, rows.Err()
+}
+
+// Pages are over bills, one row per transaction id. Bill details are never joined here
+// so LIMIT and the total count the same rows, the product filter is an EXISTS for that
+const transactionListFromQuery = `
+		FROM transaction AS t
+		INNER JOIN employee AS e ON t.employee_id = e.employee_id
+		INNER JOIN customer AS c ON t.customer_id = c.customer_id`
+
+func (tr *transactionRepository)ListTransaction(filter TransactionFilter, page entity.Page_request) ([]entity.Transaction, int, error) {
+	transactions := []entity.Transaction{}
+
+	fromQuery := transactionListFromQuery
+	qb := newTransactionQueryBuilder(filter)
+
+	total := 0
+	err := tr.DB.QueryRow("SELECT COUNT(*)"+fromQuery+qb.clause(), qb.args...).Scan(&total)
+	if err != nil {
+		return transactions, total, err
+	}
+
+	pageQuery, err := buildPageQuery(page, transactionSortColumns, "t.transaction_id", &qb)
+	if err != nil {
+		return transactions, total, err
+	}
+
+	query := `
+		SELECT t.transaction_id, t.bill_number, t.bill_date, t.entry_date, t.finish_date, t.status,
+		       t.service_tier, t.surcharge_type, t.surcharge_value, t.surcharge, ` + promoCodeQuery + `, t.discount,
+		       t.member_tier, t.member_discount_percent, t.member_discount, t.points_redeemed, t.points_discount,
+		       t.subtotal, t.tax_rate, t.tax, t.total_bill, e.employee_id, e.name, e.phone_number, e.address,
+		       c.customer_id, c.name, c.phone_number, c.address, ` + paidAmountQuery + fromQuery + qb.clause() + pageQuery
+
+	rows, err := tr.DB.Query(query, qb.args...)
+	if err != nil {
+		return transactions, total, err
+	}
+
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		transaction := entity.Transaction{}
+		err = rows.Scan(&transaction.Transaction_id,&transaction.Bill_number,&transaction.Bill_date,&transaction.Entry_date,&transaction.Finish_date,&transaction.Status,&transaction.Service_tier,&transaction.Surcharge_type,&transaction.Surcharge_value,&transaction.Surcharge,&transaction.Promo_code,&transaction.Discount,&transaction.Member_tier,&transaction.Member_discount_percent,&transaction.Member_discount,&transaction.Points_redeemed,&transaction.Points_discount,&transaction.Subtotal,&transaction.Tax_rate,&transaction.Tax,&transaction.Total_bill,&transaction.Employee.Employee_id,&transaction.Employee.Name,&transaction.Employee.Phone_number,&transaction.Employee.Address,&transaction.Customer.Customer_id,&transaction.Customer.Name,&transaction.Customer.Phone_number,&transaction.Customer.Address,&transaction.Paid_amount)
+		if err != nil {
+			return transactions, total, err
+		}
+
+		id, err := strconv.ParseInt(transaction.Transaction_id, 10, 64)
+		if err != nil {
+			return transactions, total, err
+		}
+		ids = append(ids, id)
+		transactions = append(transactions, transaction)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return transactions, total, err
+	}
+
+	if len(ids) == 0 {
+		return transactions, total, nil
+	}
+
+	details, err := tr.billDetailsOf(ids)
+	if err != nil {
+		return transactions, total, err
+	}
+
+	attachBillDetails(transactions, details)
+
+	return transactions, total, nil
+}
+
+// Bill details of the given bills only
+func (tr *transactionRepository) billDetailsOf(ids []int64) ([]entity.Transaction_detail, error) {
+	transaction_details := []entity.Transaction_detail{}
+
+	query := `SELECT 
+	td.transaction_detail_id,td.transaction_id,td.product_price,td.qty,td.line_total,td.tax_inclusive,COALESCE(td.subscription_id::text,''),td.quota_used,
+	p.product_id,p.product_name,` + priceOnQuery("p", "CURRENT_DATE") + `,p.unit,p.pricing_model,p.min_weight,p.tax_inclusive,p.product_type
+	FROM transaction_detail AS td
+	INNER JOIN product AS p ON td.product_id = p.product_id
+	WHERE td.transaction_id = ANY($1) AND td.removed_at IS NULL
+	ORDER BY td.transaction_detail_id`
+
+	rows, err := tr.DB.Query(query, pq.Array(ids))
+	if err != nil {
+		return transaction_details, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		transaction_detail := entity.Transaction_detail{}
+		err = rows.Scan(&transaction_detail.Transaction_detail_id,&transaction_detail.Transaction_id,&transaction_detail.Product_price,&transaction_detail.Qty,&transaction_detail.Line_total,&transaction_detail.Tax_inclusive,&transaction_detail.Subscription_id,&transaction_detail.Quota_used,&transaction_detail.Product.Product_id,&transaction_detail.Product.Product_name,&transaction_detail.Product.Price,&transaction_detail.Product.Unit,&transaction_detail.Product.Pricing_model,&transaction_detail.Product.Min_weight,&transaction_detail.Product.Tax_inclusive,&transaction_detail.Product.Product_type)
+		if err != nil {
+			return transaction_details, err
+		}
+		transaction_details = append(transaction_details, transaction_detail)
+	}
+
+	return transaction_details, rows.Err()
+}
+
+// Group bill details by bill id and attach them with the bill totals
+func attachBillDetails(transactions []entity.Transaction, details []entity.Transaction_detail) {
+	detailsByBill := make(map[string][]entity.Transaction_detail, len(transactions))
+	for _, detail := range details {
+		detailsByBill[detail.Transaction_id] = append(detailsByBill[detail.Transaction_id], detail)
+	}
+
+	for i := range transactions {
+		transaction := &transactions[i]
+		transaction.Bill_detail = detailsByBill[transaction.Transaction_id]
+		calculateOutstanding(transaction)
+	}
+}
+
+// Totals of a new or edited bill. Subtotal plus surcharge minus every discount is what the tax is taken from,
+// only the tax of exclusive products is added to the grand total
+func calculateBill(transaction *entity.Transaction) {
+	transaction.Subtotal = 0
+	inclusiveSubtotal := 0
+	for _, detail := range transaction.Bill_detail {
+		transaction.Subtotal += detail.Line_total
+		if detail.Tax_inclusive {
+			inclusiveSubtotal += detail.Line_total
+		}
+	}
+
+	amount := transaction.AmountBeforeTax()
+	tax, addedTax := entity.GetTax(amount, transaction.Subtotal, inclusiveSubtotal, transaction.Tax_rate)
+	transaction.Tax = tax
+	transaction.Total_bill = amount + addedTax
+	calculateOutstanding(transaction)
+}
+
+// Outstanding and payment status of the bill total
+func calculateOutstanding(transaction *entity.Transaction) {
+	transaction.Outstanding = transaction.Total_bill - transaction.Paid_amount
+	transaction.Payment_status = entity.GetPaymentStatus(transaction.Total_bill, transaction.Paid_amount)
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"submission-project-enigma-laundry/entity"
+	"testing"
+	"time"
+)
+
+func TestAttachBillDetails(t *testing.T) {
+	transactions := []entity.Transaction{
+		{Transaction_id: "1", Subtotal: 35000, Total_bill: 35000, Paid_amount: 10000},
+		{Transaction_id: "2", Subtotal: 5000, Surcharge: 2500, Total_bill: 7500, Paid_amount: 7500},
+		{Transaction_id: "3"},
+	}
+	details := []entity.Transaction_detail{
+		{Transaction_detail_id: "1", Transaction_id: "1", Product_price: 10000, Qty: 2, Line_total: 20000},
+		{Transaction_detail_id: "2", Transaction_id: "2", Product_price: 5000, Qty: 1, Line_total: 5000},
+		{Transaction_detail_id: "3", Transaction_id: "1", Product_price: 15000, Qty: 1, Line_total: 15000},
+	}
+
+	attachBillDetails(transactions, details)
+
+	if len(transactions[0].Bill_detail) != 2 || transactions[0].Total_bill != 35000 {
+		t.Fatalf("bill 1 got %d details and total %d", len(transactions[0].Bill_detail), transactions[0].Total_bill)
+	}
+	if transactions[0].Outstanding != 25000 || transactions[0].Payment_status != entity.Payment_status_partial {
+		t.Fatalf("bill 1 got outstanding %d and status %s", transactions[0].Outstanding, transactions[0].Payment_status)
+	}
+	if len(transactions[1].Bill_detail) != 1 || transactions[1].Outstanding != 0 || transactions[1].Payment_status != entity.Payment_status_paid {
+		t.Fatalf("bill 2 got %d details, outstanding %d and status %s", len(transactions[1].Bill_detail), transactions[1].Outstanding, transactions[1].Payment_status)
+	}
+	if len(transactions[2].Bill_detail) != 0 || transactions[2].Total_bill != 0 {
+		t.Fatalf("bill 3 got %d details and total %d", len(transactions[2].Bill_detail), transactions[2].Total_bill)
+	}
+}
+
+func TestCalculateBill(t *testing.T) {
+	// 11% PPN, Rp 11.100 inclusive and Rp 10.000 exclusive with a Rp 2.000 surcharge and Rp 1.000 discount
+	transaction := entity.Transaction{
+		Bill_detail: []entity.Transaction_detail{
+			{Line_total: 11100, Tax_inclusive: true},
+			{Line_total: 10000},
+		},
+		Surcharge: 2000,
+		Discount:  1000,
+		Tax_rate:  1100,
+	}
+
+	calculateBill(&transaction)
+
+	// Rp 22.100 to pay before tax, Rp 11.626 of it is inclusive with Rp 1.152 tax, Rp 10.474 exclusive gets Rp 1.152 added
+	if transaction.Subtotal != 21100 || transaction.Tax != 2304 || transaction.Total_bill != 23252 {
+		t.Fatalf("got subtotal %d, tax %d and total %d", transaction.Subtotal, transaction.Tax, transaction.Total_bill)
+	}
+	if transaction.Outstanding != 23252 || transaction.Payment_status != entity.Payment_status_unpaid {
+		t.Fatalf("got outstanding %d and status %s", transaction.Outstanding, transaction.Payment_status)
+	}
+}
+
+func TestCalculateBillMemberAndPoints(t *testing.T) {
+	// Silver member with 5% off Rp 20.000 and 50 points redeemed, no tax
+	transaction := entity.Transaction{
+		Bill_detail:     []entity.Transaction_detail{{Line_total: 20000}},
+		Member_discount: entity.GetMemberDiscount(5, 20000),
+		Points_redeemed: 50,
+		Points_discount: 50 * entity.Point_value,
+	}
+
+	calculateBill(&transaction)
+
+	if transaction.Member_discount != 1000 || transaction.Total_bill != 18500 {
+		t.Fatalf("got member discount %d and total %d", transaction.Member_discount, transaction.Total_bill)
+	}
+	if entity.GetEarnedPoints(transaction.Total_bill) != 18 {
+		t.Fatalf("got %d earned points", entity.GetEarnedPoints(transaction.Total_bill))
+	}
+}
+
+func TestUsesStock(t *testing.T) {
+	onCreate := transactionRepository{}
+	onFinish := transactionRepository{stockOnFinish: true}
+
+	for _, status := range []string{entity.Status_received, entity.Status_washing, entity.Status_ready, entity.Status_picked_up, entity.Status_cancelled} {
+		finished := status == entity.Status_ready || status == entity.Status_picked_up
+		if onCreate.usesStock(status) != (status != entity.Status_cancelled) {
+			t.Fatalf("deducted on create, %s got %v", status, onCreate.usesStock(status))
+		}
+		if onFinish.usesStock(status) != finished {
+			t.Fatalf("deducted on finish, %s got %v", status, onFinish.usesStock(status))
+		}
+	}
+}
+
+// Bills of a page are read once and their details with one more query, whatever the page size
+func TestListTransactionLoadsDetailsPerPage(t *testing.T) {
+	billDate := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	db := fakeDB{respond: func(query string, args []driver.NamedValue) (int, [][]driver.Value) {
+		switch {
+		case strings.HasPrefix(query, "SELECT COUNT(*)"):
+			return 1, [][]driver.Value{{int64(25)}}
+		case strings.Contains(query, "ANY($1)"):
+			// 2 details for every bill of the page
+			ids := strings.Split(strings.Trim(args[0].Value.(string), "{}"), ",")
+			values := [][]driver.Value{}
+			for _, id := range ids {
+				for j := 0; j < 2; j++ {
+					values = append(values, []driver.Value{"1", id, "5000", "1", "5000", "1", "", "0", "1", "Cuci", "5000", "kg", entity.Pricing_per_kg, "0", "1", entity.Product_type_service})
+				}
+			}
+			return 16, values
+		}
+		// A page of bills, ids continue from the offset
+		size, offset := args[len(args)-2].Value.(int64), args[len(args)-1].Value.(int64)
+		values := [][]driver.Value{}
+		for i := offset + 1; i <= min(offset+size, 25); i++ {
+			row := []driver.Value{strconv.FormatInt(i, 10), "EL-JKT-20241001-0001", billDate, billDate, billDate}
+			for len(row) < 30 {
+				row = append(row, "1")
+			}
+			values = append(values, row)
+		}
+		return 30, values
+	}}
+	repository := transactionRepository{DB: db.open()}
+
+	for page := 1; page <= 3; page++ {
+		db.queries = nil
+		transactions, total, err := repository.ListTransaction(TransactionFilter{}, entity.Page_request{Page: page, Size: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expectedBills := []int{10, 10, 5}[page-1]
+		if total != 25 || len(transactions) != expectedBills {
+			t.Fatalf("page %d got %d bills of %d", page, len(transactions), total)
+		}
+		for _, transaction := range transactions {
+			if len(transaction.Bill_detail) != 2 {
+				t.Fatalf("page %d bill %s got %d details", page, transaction.Transaction_id, len(transaction.Bill_detail))
+			}
+		}
+
+		if len(db.queries) != 3 {
+			t.Fatalf("page %d expected 3 queries, got %d", page, len(db.queries))
+		}
+		pageQuery, detailQuery := db.queries[1], db.queries[2]
+		if strings.Contains(pageQuery.query, "transaction_detail") || !strings.Contains(pageQuery.query, "LIMIT $1 OFFSET $2") {
+			t.Fatalf("page %d bill details joined before the limit %q", page, pageQuery.query)
+		}
+		if !strings.Contains(detailQuery.query, "td.transaction_id = ANY($1)") || len(detailQuery.args) != 1 {
+			t.Fatalf("page %d unexpected detail query %q", page, detailQuery.query)
+		}
+		expectedIds := fmt.Sprintf("{%d", (page-1)*10+1)
+		if !strings.HasPrefix(detailQuery.args[0].Value.(string), expectedIds) {
+			t.Fatalf("page %d details loaded for %v", page, detailQuery.args[0].Value)
+		}
+	}
+
+	// An empty page has no detail query
+	db.queries = nil
+	_, _, err := repository.ListTransaction(TransactionFilter{}, entity.Page_request{Page: 4, Size: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(db.queries) != 2 {
+		t.Fatalf("empty page expected 2 queries, got %d", len(db.queries))
+	}
+}
+
+func TestFormatBillNumber(t *testing.T) {
+	billDate := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		branchCode string
+		number     int
+		expected   string
+	}{
+		{"JKT", 1, "EL-JKT-20241001-0001"},
+		{"JKT", 42, "EL-JKT-20241001-0042"},
+		{"BDG", 9999, "EL-BDG-20241001-9999"},
+		{"BDG", 10000, "EL-BDG-20241001-10000"},
+	}
+
+	for _, test := range tests {
+		if got := formatBillNumber(test.branchCode, billDate, test.number); got != test.expected {
+			t.Fatalf("expected %q, got %q", test.expected, got)
+		}
+	}
+}
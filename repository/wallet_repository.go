@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"submission-project-enigma-laundry/entity"
+	_ "github.com/lib/pq"
+)
+
+type WalletRepository interface {
+	GetWallet(customerId int) (*entity.Customer_wallet, error)
+	TopUp(customerId int, entry *entity.Wallet_entry) (*entity.Wallet_entry, error)
+}
+
+// Returned when the wallet balance can not cover a debit
+var ErrWalletInsufficient = errors.New("wallet balance is not enough")
+
+type walletRepository struct {
+	DB *sql.DB
+}
+
+func NewWalletRepo(db *sql.DB) WalletRepository {
+	return &walletRepository{DB: db}
+}
+
+func (wr *walletRepository) GetWallet(customerId int) (*entity.Customer_wallet, error) {
+	wallet := entity.Customer_wallet{Ledger: []entity.Wallet_entry{}}
+
+	query := "SELECT c.customer_id,COALESCE(w.balance,0) FROM customer AS c LEFT JOIN customer_wallet AS w ON c.customer_id = w.customer_id WHERE c.customer_id = $1"
+	err := wr.DB.QueryRow(query, customerId).Scan(&wallet.Customer_id, &wallet.Balance)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = errors.New("customer not found")
+			return &wallet, err
+		}
+		return &wallet, err
+	}
+
+	ledger := `SELECT we.wallet_entry_id,we.customer_id,COALESCE(we.transaction_id::text,''),COALESCE(t.bill_number,''),COALESCE(we.payment_id::text,''),
+	we.amount,we.balance_after,we.entry_type,we.method,we.note,we.created_at
+	FROM wallet_entry AS we
+	LEFT JOIN transaction AS t ON we.transaction_id = t.transaction_id
+	WHERE we.customer_id = $1 ORDER BY we.created_at DESC,we.wallet_entry_id DESC`
+
+	rows, err := wr.DB.Query(ledger, customerId)
+	if err != nil {
+		return &wallet, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		entry := entity.Wallet_entry{}
+		err = rows.Scan(&entry.Wallet_entry_id, &entry.Customer_id, &entry.Transaction_id, &entry.Bill_number, &entry.Payment_id,
+			&entry.Amount, &entry.Balance_after, &entry.Entry_type, &entry.Method, &entry.Note, &entry.Created_at)
+		if err != nil {
+			return &wallet, err
+		}
+		wallet.Ledger = append(wallet.Ledger, entry)
+	}
+
+	return &wallet, rows.Err()
+}
+
+func (wr *walletRepository) TopUp(customerId int, entry *entity.Wallet_entry) (*entity.Wallet_entry, error) {
+	tx, err := wr.DB.Begin()
+	if err != nil {
+		err = fmt.Errorf("failed starting transaction , %s", err)
+		return entry, err
+	}
+
+	err = tx.QueryRow("SELECT customer_id FROM customer WHERE customer_id = $1", customerId).Scan(&entry.Customer_id)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			err = errors.New("customer not found")
+			return entry, err
+		}
+		return entry, err
+	}
+
+	entry.Entry_type = entity.Wallet_top_up
+	err = insertWalletEntry(tx, entry)
+	if err != nil {
+		tx.Rollback()
+		return entry, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		err = fmt.Errorf("failed commit transaction , %s", err)
+		return entry, err
+	}
+
+	return entry, nil
+}
+
+// Lock the wallet row so concurrent debits wait for each other and can not overdraw it,
+// the wallet is made on its first entry
+func insertWalletEntry(tx *sql.Tx, entry *entity.Wallet_entry) error {
+	createWallet := "INSERT INTO customer_wallet (customer_id,balance) VALUES ($1,0) ON CONFLICT (customer_id) DO NOTHING"
+	_, err := tx.Exec(createWallet, entry.Customer_id)
+	if err != nil {
+		err = fmt.Errorf("failed insert into customer wallet , %s", err)
+		return err
+	}
+
+	balance := 0
+	err = tx.QueryRow("SELECT balance FROM customer_wallet WHERE customer_id = $1 FOR UPDATE", entry.Customer_id).Scan(&balance)
+	if err != nil {
+		return err
+	}
+
+	if balance+entry.Amount < 0 {
+		return fmt.Errorf("%w, balance is %d", ErrWalletInsufficient, balance)
+	}
+	entry.Balance_after = balance + entry.Amount
+
+	_, err = tx.Exec("UPDATE customer_wallet SET balance = $2, updated_at = CURRENT_TIMESTAMP WHERE customer_id = $1", entry.Customer_id, entry.Balance_after)
+	if err != nil {
+		err = fmt.Errorf("failed update customer wallet , %s", err)
+		return err
+	}
+
+	createEntry := `INSERT INTO wallet_entry (customer_id,transaction_id,payment_id,amount,balance_after,entry_type,method,note)
+	VALUES ($1,NULLIF($2,'')::int,NULLIF($3,'')::int,$4,$5,$6,$7,$8) RETURNING wallet_entry_id,created_at`
+
+	err = tx.QueryRow(createEntry, entry.Customer_id, entry.Transaction_id, entry.Payment_id, entry.Amount, entry.Balance_after, entry.Entry_type, entry.Method, entry.Note).Scan(&entry.Wallet_entry_id, &entry.Created_at)
+	if err != nil {
+		err = fmt.Errorf("failed insert into wallet entry , %s", err)
+		return err
+	}
+	return nil
+}
+
+// Debit the wallet of the customer for a payment made with it
+func debitWallet(tx *sql.Tx, customerId string, payment *entity.Payment) error {
+	return insertWalletEntry(tx, &entity.Wallet_entry{Customer_id: customerId, Transaction_id: payment.Transaction_id, Payment_id: payment.Payment_id, Amount: -payment.Amount, Entry_type: entity.Wallet_debit, Note: payment.Payment_type})
+}
+
+// Give back what a cancelled bill took from the wallet
+func refundWallet(tx *sql.Tx, transactionId string, customerId string) error {
+	debited := 0
+	query := "SELECT COALESCE(-SUM(amount),0) FROM wallet_entry WHERE transaction_id = $1 AND entry_type IN ('debit','refund')"
+	err := tx.QueryRow(query, transactionId).Scan(&debited)
+	if err != nil {
+		return err
+	}
+
+	if debited <= 0 {
+		return nil
+	}
+	return insertWalletEntry(tx, &entity.Wallet_entry{Customer_id: customerId, Transaction_id: transactionId, Amount: debited, Entry_type: entity.Wallet_refund, Note: "bill cancelled"})
+}
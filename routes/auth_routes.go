@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"submission-project-enigma-laundry/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+
+func Auth(router *gin.Engine, ac controller.AuthController) {
+	authRoutes := router.Group("/auth")
+	{
+		authRoutes.POST("/login",ac.Login)
+		authRoutes.POST("/refresh",ac.Refresh)
+	}
+}
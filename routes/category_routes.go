@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"submission-project-enigma-laundry/controller"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+
+func Category(router *gin.Engine, cc controller.CategoryController, am middleware.AuthMiddleware) {
+	categoryRoutes := router.Group("/categories", am.RequireToken())
+	{
+		categoryRoutes.GET("/",cc.ListCategory)
+		categoryRoutes.POST("/",am.RequireRole(entity.Role_admin),cc.CreateCategory)
+		categoryRoutes.PUT("/:id",am.RequireRole(entity.Role_admin),cc.UpdateCategory)
+	}
+
+	router.GET("/catalogue",am.RequireToken(),cc.GetCatalogue)
+}
\ No newline at end of file
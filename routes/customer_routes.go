@@ -1,19 +1,29 @@
-package routes
-
-import (
-	"submission-project-enigma-laundry/controller"
-
-	"github.com/gin-gonic/gin"
-)
-
-
-func Customer(router *gin.Engine, cc controller.CustomerController) {
-	customerRoutes := router.Group("/customers")
-	{
-		customerRoutes.GET("/",cc.GetAllCustomer)
-		customerRoutes.GET("/:id",cc.GetDetailCustomer)
-		customerRoutes.POST("/", cc.CreateCustomer)
-		customerRoutes.PUT("/:id",cc.UpdateCustomer)
-		customerRoutes.DELETE("/:id",cc.DeleteCustomer)
-	}
+package routes
+
+import (
+	"submission-project-enigma-laundry/controller"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+
+func Customer(router *gin.Engine, cc controller.CustomerController, am middleware.AuthMiddleware) {
+	customerRoutes := router.Group("/customers", am.RequireToken())
+	{
+		customerRoutes.GET("/",cc.GetAllCustomer)
+		customerRoutes.GET("/:id",cc.GetDetailCustomer)
+		customerRoutes.GET("/:id/transactions",cc.GetCustomerTransactions)
+		customerRoutes.GET("/:id/points",cc.GetCustomerPoints)
+		customerRoutes.GET("/:id/wallet",cc.GetCustomerWallet)
+		customerRoutes.POST("/:id/wallet/top-ups",am.RequireRole(entity.Role_admin, entity.Role_cashier),cc.TopUpWallet)
+		customerRoutes.GET("/:id/subscriptions",cc.GetCustomerSubscriptions)
+		customerRoutes.POST("/:id/subscriptions",am.RequireRole(entity.Role_admin, entity.Role_cashier),cc.CreateSubscription)
+		customerRoutes.POST("/", am.RequireRole(entity.Role_admin, entity.Role_cashier), cc.CreateCustomer)
+		customerRoutes.PUT("/:id",am.RequireRole(entity.Role_admin, entity.Role_cashier),cc.UpdateCustomer)
+		customerRoutes.DELETE("/:id",am.RequireRole(entity.Role_admin),cc.DeleteCustomer)
+		customerRoutes.POST("/:id/restore",am.RequireRole(entity.Role_admin),cc.RestoreCustomer)
+		customerRoutes.POST("/:id/merge",am.RequireRole(entity.Role_admin),cc.MergeCustomer)
+	}
 }
\ No newline at end of file
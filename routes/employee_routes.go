@@ -1,19 +1,24 @@
-package routes
-
-import (
-	"submission-project-enigma-laundry/controller"
-
-	"github.com/gin-gonic/gin"
-)
-
-
-func Employee(router *gin.Engine, ec controller.EmployeeController) {
-	employeeRoutes := router.Group("/employees")
-	{
-		employeeRoutes.GET("/",ec.GetAllEmployee)
-		employeeRoutes.GET("/:id",ec.GetDetailEmployee)
-		employeeRoutes.POST("/", ec.CreateEmployee)
-		employeeRoutes.PUT("/:id",ec.UpdateEmployee)
-		employeeRoutes.DELETE("/:id",ec.DeleteEmployee)
-	}
+package routes
+
+import (
+	"submission-project-enigma-laundry/controller"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+
+func Employee(router *gin.Engine, ec controller.EmployeeController, am middleware.AuthMiddleware) {
+	employeeRoutes := router.Group("/employees", am.RequireToken(), am.RequireRole(entity.Role_admin))
+	{
+		employeeRoutes.GET("/",ec.GetAllEmployee)
+		employeeRoutes.GET("/:id",ec.GetDetailEmployee)
+		employeeRoutes.POST("/", ec.CreateEmployee)
+		employeeRoutes.PUT("/:id",ec.UpdateEmployee)
+		employeeRoutes.DELETE("/:id",ec.DeleteEmployee)
+		employeeRoutes.POST("/:id/restore",ec.RestoreEmployee)
+		employeeRoutes.POST("/:id/roles",ec.AssignRole)
+		employeeRoutes.DELETE("/:id/roles/:role",ec.RevokeRole)
+	}
 }
\ No newline at end of file
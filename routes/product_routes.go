@@ -1,19 +1,23 @@
-package routes
-
-import (
-	"submission-project-enigma-laundry/controller"
-
-	"github.com/gin-gonic/gin"
-)
-
-
-func Product(router *gin.Engine, pc controller.ProductController) {
-	productRoutes := router.Group("/products")
-	{
-		productRoutes.GET("/",pc.ListProduct)
-		productRoutes.GET("/:id",pc.GetDetailProduct)
-		productRoutes.POST("/", pc.CreateProduct)
-		productRoutes.PUT("/:id",pc.UpdateProduct)
-		productRoutes.DELETE("/:id",pc.DeleteProduct)
-	}
+package routes
+
+import (
+	"submission-project-enigma-laundry/controller"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+
+func Product(router *gin.Engine, pc controller.ProductController, am middleware.AuthMiddleware) {
+	productRoutes := router.Group("/products", am.RequireToken())
+	{
+		productRoutes.GET("/",pc.ListProduct)
+		productRoutes.GET("/:id",pc.GetDetailProduct)
+		productRoutes.GET("/:id/prices",pc.GetProductPrices)
+		productRoutes.POST("/", am.RequireRole(entity.Role_admin), pc.CreateProduct)
+		productRoutes.PUT("/:id",am.RequireRole(entity.Role_admin),pc.UpdateProduct)
+		productRoutes.DELETE("/:id",am.RequireRole(entity.Role_admin),pc.DeleteProduct)
+		productRoutes.POST("/:id/restore",am.RequireRole(entity.Role_admin),pc.RestoreProduct)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"submission-project-enigma-laundry/controller"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+
+func Promo(router *gin.Engine, pc controller.PromoController, am middleware.AuthMiddleware) {
+	promoRoutes := router.Group("/promos", am.RequireToken())
+	{
+		promoRoutes.GET("/",pc.ListPromo)
+		promoRoutes.GET("/:code",pc.GetPromo)
+		promoRoutes.POST("/",am.RequireRole(entity.Role_admin),pc.CreatePromo)
+		promoRoutes.PUT("/:code",am.RequireRole(entity.Role_admin),pc.UpdatePromo)
+	}
+}
\ No newline at end of file
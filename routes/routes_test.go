@@ -0,0 +1,305 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"submission-project-enigma-laundry/config"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/middleware"
+	"submission-project-enigma-laundry/utils"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Answers every route with 200 and remembers which handler was reached
+type fakeController struct {
+	called string
+}
+
+func (fc *fakeController) handle(ctx *gin.Context, name string) {
+	fc.called = name
+	ctx.Status(http.StatusOK)
+}
+
+func (fc *fakeController) Login(ctx *gin.Context)             { fc.handle(ctx, "Login") }
+func (fc *fakeController) Refresh(ctx *gin.Context)           { fc.handle(ctx, "Refresh") }
+func (fc *fakeController) CreateCustomer(ctx *gin.Context)    { fc.handle(ctx, "CreateCustomer") }
+func (fc *fakeController) GetAllCustomer(ctx *gin.Context)    { fc.handle(ctx, "GetAllCustomer") }
+func (fc *fakeController) GetDetailCustomer(ctx *gin.Context) { fc.handle(ctx, "GetDetailCustomer") }
+func (fc *fakeController) UpdateCustomer(ctx *gin.Context)    { fc.handle(ctx, "UpdateCustomer") }
+func (fc *fakeController) DeleteCustomer(ctx *gin.Context)    { fc.handle(ctx, "DeleteCustomer") }
+func (fc *fakeController) RestoreCustomer(ctx *gin.Context)   { fc.handle(ctx, "RestoreCustomer") }
+func (fc *fakeController) MergeCustomer(ctx *gin.Context)     { fc.handle(ctx, "MergeCustomer") }
+func (fc *fakeController) GetCustomerTransactions(ctx *gin.Context) {
+	fc.handle(ctx, "GetCustomerTransactions")
+}
+func (fc *fakeController) GetCustomerPoints(ctx *gin.Context) { fc.handle(ctx, "GetCustomerPoints") }
+func (fc *fakeController) GetCustomerWallet(ctx *gin.Context) { fc.handle(ctx, "GetCustomerWallet") }
+func (fc *fakeController) TopUpWallet(ctx *gin.Context)       { fc.handle(ctx, "TopUpWallet") }
+func (fc *fakeController) GetCustomerSubscriptions(ctx *gin.Context) {
+	fc.handle(ctx, "GetCustomerSubscriptions")
+}
+func (fc *fakeController) CreateSubscription(ctx *gin.Context) { fc.handle(ctx, "CreateSubscription") }
+func (fc *fakeController) CreateEmployee(ctx *gin.Context)     { fc.handle(ctx, "CreateEmployee") }
+func (fc *fakeController) GetAllEmployee(ctx *gin.Context)     { fc.handle(ctx, "GetAllEmployee") }
+func (fc *fakeController) GetDetailEmployee(ctx *gin.Context)  { fc.handle(ctx, "GetDetailEmployee") }
+func (fc *fakeController) UpdateEmployee(ctx *gin.Context)     { fc.handle(ctx, "UpdateEmployee") }
+func (fc *fakeController) DeleteEmployee(ctx *gin.Context)     { fc.handle(ctx, "DeleteEmployee") }
+func (fc *fakeController) RestoreEmployee(ctx *gin.Context)    { fc.handle(ctx, "RestoreEmployee") }
+func (fc *fakeController) AssignRole(ctx *gin.Context)         { fc.handle(ctx, "AssignRole") }
+func (fc *fakeController) RevokeRole(ctx *gin.Context)         { fc.handle(ctx, "RevokeRole") }
+func (fc *fakeController) CreateProduct(ctx *gin.Context)      { fc.handle(ctx, "CreateProduct") }
+func (fc *fakeController) ListProduct(ctx *gin.Context)        { fc.handle(ctx, "ListProduct") }
+func (fc *fakeController) GetDetailProduct(ctx *gin.Context)   { fc.handle(ctx, "GetDetailProduct") }
+func (fc *fakeController) UpdateProduct(ctx *gin.Context)      { fc.handle(ctx, "UpdateProduct") }
+func (fc *fakeController) DeleteProduct(ctx *gin.Context)      { fc.handle(ctx, "DeleteProduct") }
+func (fc *fakeController) RestoreProduct(ctx *gin.Context)     { fc.handle(ctx, "RestoreProduct") }
+func (fc *fakeController) GetProductPrices(ctx *gin.Context)   { fc.handle(ctx, "GetProductPrices") }
+func (fc *fakeController) CreateTransaction(ctx *gin.Context)  { fc.handle(ctx, "CreateTransaction") }
+func (fc *fakeController) GetTransaction(ctx *gin.Context)     { fc.handle(ctx, "GetTransaction") }
+func (fc *fakeController) ListTransaction(ctx *gin.Context)    { fc.handle(ctx, "ListTransaction") }
+func (fc *fakeController) UpdateTransactionStatus(ctx *gin.Context) {
+	fc.handle(ctx, "UpdateTransactionStatus")
+}
+func (fc *fakeController) CreatePayment(ctx *gin.Context)      { fc.handle(ctx, "CreatePayment") }
+func (fc *fakeController) UpdateTransaction(ctx *gin.Context)  { fc.handle(ctx, "UpdateTransaction") }
+func (fc *fakeController) GetReceipt(ctx *gin.Context)         { fc.handle(ctx, "GetReceipt") }
+func (fc *fakeController) CancelTransaction(ctx *gin.Context)  { fc.handle(ctx, "CancelTransaction") }
+func (fc *fakeController) ListServiceTier(ctx *gin.Context)    { fc.handle(ctx, "ListServiceTier") }
+func (fc *fakeController) CreateServiceTier(ctx *gin.Context)  { fc.handle(ctx, "CreateServiceTier") }
+func (fc *fakeController) UpdateServiceTier(ctx *gin.Context)  { fc.handle(ctx, "UpdateServiceTier") }
+func (fc *fakeController) ListPromo(ctx *gin.Context)          { fc.handle(ctx, "ListPromo") }
+func (fc *fakeController) GetPromo(ctx *gin.Context)           { fc.handle(ctx, "GetPromo") }
+func (fc *fakeController) CreatePromo(ctx *gin.Context)        { fc.handle(ctx, "CreatePromo") }
+func (fc *fakeController) UpdatePromo(ctx *gin.Context)        { fc.handle(ctx, "UpdatePromo") }
+func (fc *fakeController) ListCategory(ctx *gin.Context)       { fc.handle(ctx, "ListCategory") }
+func (fc *fakeController) CreateCategory(ctx *gin.Context)     { fc.handle(ctx, "CreateCategory") }
+func (fc *fakeController) UpdateCategory(ctx *gin.Context)     { fc.handle(ctx, "UpdateCategory") }
+func (fc *fakeController) GetCatalogue(ctx *gin.Context)       { fc.handle(ctx, "GetCatalogue") }
+func (fc *fakeController) ListSupply(ctx *gin.Context)         { fc.handle(ctx, "ListSupply") }
+func (fc *fakeController) GetLowStockReport(ctx *gin.Context)  { fc.handle(ctx, "GetLowStockReport") }
+func (fc *fakeController) GetSupply(ctx *gin.Context)          { fc.handle(ctx, "GetSupply") }
+func (fc *fakeController) CreateSupply(ctx *gin.Context)       { fc.handle(ctx, "CreateSupply") }
+func (fc *fakeController) UpdateSupply(ctx *gin.Context)       { fc.handle(ctx, "UpdateSupply") }
+func (fc *fakeController) AdjustStock(ctx *gin.Context)        { fc.handle(ctx, "AdjustStock") }
+func (fc *fakeController) GetProductSupplies(ctx *gin.Context) { fc.handle(ctx, "GetProductSupplies") }
+func (fc *fakeController) SetProductSupplies(ctx *gin.Context) { fc.handle(ctx, "SetProductSupplies") }
+
+var testJwtConfig = config.JwtConfig{
+	Secret:      []byte("test-secret"),
+	Access_ttl:  time.Minute,
+	Refresh_ttl: time.Hour,
+}
+
+func newTestRouter(fc *fakeController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	am := middleware.NewAuthMiddleware(testJwtConfig)
+
+	Auth(router, fc)
+	Customer(router, fc, am)
+	Employee(router, fc, am)
+	Product(router, fc, am)
+	Transaction(router, fc, am)
+	ServiceTier(router, fc, am)
+	Promo(router, fc, am)
+	Category(router, fc, am)
+	Supply(router, fc, am)
+	return router
+}
+
+func accessToken(t *testing.T, roles ...string) string {
+	tokens, err := utils.GenerateTokenPair(testJwtConfig, 1, roles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokens.Access_token
+}
+
+var (
+	anyRole = []string{entity.Role_admin, entity.Role_cashier, entity.Role_washer}
+	cashier = []string{entity.Role_admin, entity.Role_cashier}
+	admin   = []string{entity.Role_admin}
+)
+
+// Roles allowed on every protected route
+var routePermissions = []struct {
+	method  string
+	path    string
+	handler string
+	allowed []string
+}{
+	{http.MethodGet, "/customers/", "GetAllCustomer", anyRole},
+	{http.MethodGet, "/customers/1", "GetDetailCustomer", anyRole},
+	{http.MethodGet, "/customers/1/transactions", "GetCustomerTransactions", anyRole},
+	{http.MethodGet, "/customers/1/points", "GetCustomerPoints", anyRole},
+	{http.MethodGet, "/customers/1/wallet", "GetCustomerWallet", anyRole},
+	{http.MethodPost, "/customers/1/wallet/top-ups", "TopUpWallet", cashier},
+	{http.MethodGet, "/customers/1/subscriptions", "GetCustomerSubscriptions", anyRole},
+	{http.MethodPost, "/customers/1/subscriptions", "CreateSubscription", cashier},
+	{http.MethodPost, "/customers/", "CreateCustomer", cashier},
+	{http.MethodPut, "/customers/1", "UpdateCustomer", cashier},
+	{http.MethodDelete, "/customers/1", "DeleteCustomer", admin},
+	{http.MethodPost, "/customers/1/restore", "RestoreCustomer", admin},
+	{http.MethodPost, "/customers/1/merge", "MergeCustomer", admin},
+
+	{http.MethodGet, "/employees/", "GetAllEmployee", admin},
+	{http.MethodGet, "/employees/1", "GetDetailEmployee", admin},
+	{http.MethodPost, "/employees/", "CreateEmployee", admin},
+	{http.MethodPut, "/employees/1", "UpdateEmployee", admin},
+	{http.MethodDelete, "/employees/1", "DeleteEmployee", admin},
+	{http.MethodPost, "/employees/1/restore", "RestoreEmployee", admin},
+	{http.MethodPost, "/employees/1/roles", "AssignRole", admin},
+	{http.MethodDelete, "/employees/1/roles/cashier", "RevokeRole", admin},
+
+	{http.MethodGet, "/products/", "ListProduct", anyRole},
+	{http.MethodGet, "/products/1", "GetDetailProduct", anyRole},
+	{http.MethodGet, "/products/1/prices", "GetProductPrices", anyRole},
+	{http.MethodPost, "/products/", "CreateProduct", admin},
+	{http.MethodPut, "/products/1", "UpdateProduct", admin},
+	{http.MethodDelete, "/products/1", "DeleteProduct", admin},
+	{http.MethodPost, "/products/1/restore", "RestoreProduct", admin},
+
+	{http.MethodPost, "/transactions/", "CreateTransaction", cashier},
+	{http.MethodGet, "/transactions/1", "GetTransaction", anyRole},
+	{http.MethodGet, "/transactions/", "ListTransaction", anyRole},
+	{http.MethodGet, "/transactions/1/receipt", "GetReceipt", anyRole},
+	{http.MethodPut, "/transactions/1", "UpdateTransaction", cashier},
+	{http.MethodPost, "/transactions/1/cancel", "CancelTransaction", cashier},
+	{http.MethodPatch, "/transactions/1/status", "UpdateTransactionStatus", anyRole},
+	{http.MethodPost, "/transactions/1/payments", "CreatePayment", cashier},
+
+	{http.MethodGet, "/service-tiers/", "ListServiceTier", anyRole},
+	{http.MethodPost, "/service-tiers/", "CreateServiceTier", admin},
+	{http.MethodPut, "/service-tiers/express", "UpdateServiceTier", admin},
+
+	{http.MethodGet, "/promos/", "ListPromo", anyRole},
+	{http.MethodGet, "/promos/LEBARAN10", "GetPromo", anyRole},
+	{http.MethodPost, "/promos/", "CreatePromo", admin},
+	{http.MethodPut, "/promos/LEBARAN10", "UpdatePromo", admin},
+
+	{http.MethodGet, "/categories/", "ListCategory", anyRole},
+	{http.MethodPost, "/categories/", "CreateCategory", admin},
+	{http.MethodPut, "/categories/1", "UpdateCategory", admin},
+	{http.MethodGet, "/catalogue", "GetCatalogue", anyRole},
+
+	{http.MethodGet, "/supplies/", "ListSupply", anyRole},
+	{http.MethodGet, "/supplies/low-stock", "GetLowStockReport", anyRole},
+	{http.MethodGet, "/supplies/1", "GetSupply", anyRole},
+	{http.MethodPost, "/supplies/", "CreateSupply", admin},
+	{http.MethodPut, "/supplies/1", "UpdateSupply", admin},
+	{http.MethodPost, "/supplies/1/adjustments", "AdjustStock", cashier},
+	{http.MethodGet, "/products/1/supplies", "GetProductSupplies", anyRole},
+	{http.MethodPut, "/products/1/supplies", "SetProductSupplies", admin},
+}
+
+func contains(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRoutePermissions(t *testing.T) {
+	for _, route := range routePermissions {
+		for _, role := range anyRole {
+			t.Run(route.method+" "+route.path+" as "+role, func(t *testing.T) {
+				fc := &fakeController{}
+				router := newTestRouter(fc)
+
+				request := httptest.NewRequest(route.method, route.path, nil)
+				request.Header.Set("Authorization", "Bearer "+accessToken(t, role))
+				recorder := httptest.NewRecorder()
+				router.ServeHTTP(recorder, request)
+
+				if contains(route.allowed, role) {
+					if recorder.Code != http.StatusOK || fc.called != route.handler {
+						t.Fatalf("expected %s to reach %s, got status %d and handler %q", role, route.handler, recorder.Code, fc.called)
+					}
+					return
+				}
+				if recorder.Code != http.StatusForbidden || fc.called != "" {
+					t.Fatalf("expected %s to be forbidden, got status %d and handler %q", role, recorder.Code, fc.called)
+				}
+			})
+		}
+	}
+}
+
+func TestRoutesRequireToken(t *testing.T) {
+	for _, route := range routePermissions {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			fc := &fakeController{}
+			router := newTestRouter(fc)
+
+			request := httptest.NewRequest(route.method, route.path, nil)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != http.StatusUnauthorized || fc.called != "" {
+				t.Fatalf("expected unauthorized, got status %d and handler %q", recorder.Code, fc.called)
+			}
+		})
+	}
+}
+
+func TestRoutesRejectRefreshToken(t *testing.T) {
+	tokens, err := utils.GenerateTokenPair(testJwtConfig, 1, admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fc := &fakeController{}
+	router := newTestRouter(fc)
+
+	request := httptest.NewRequest(http.MethodGet, "/customers/", nil)
+	request.Header.Set("Authorization", "Bearer "+tokens.Refresh_token)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusUnauthorized || fc.called != "" {
+		t.Fatalf("expected unauthorized, got status %d and handler %q", recorder.Code, fc.called)
+	}
+}
+
+func TestEmployeeWithoutRole(t *testing.T) {
+	fc := &fakeController{}
+	router := newTestRouter(fc)
+
+	request := httptest.NewRequest(http.MethodGet, "/transactions/", nil)
+	request.Header.Set("Authorization", "Bearer "+accessToken(t))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected read routes open to any logged in employee, got status %d", recorder.Code)
+	}
+
+	request = httptest.NewRequest(http.MethodPost, "/transactions/", nil)
+	request.Header.Set("Authorization", "Bearer "+accessToken(t))
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected forbidden, got status %d", recorder.Code)
+	}
+}
+
+func TestAuthRoutesArePublic(t *testing.T) {
+	for _, path := range []string{"/auth/login", "/auth/refresh"} {
+		fc := &fakeController{}
+		router := newTestRouter(fc)
+
+		request := httptest.NewRequest(http.MethodPost, path, nil)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("expected %s to be public, got status %d", path, recorder.Code)
+		}
+	}
+}
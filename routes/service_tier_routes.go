@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"submission-project-enigma-laundry/controller"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+
+func ServiceTier(router *gin.Engine, sc controller.ServiceTierController, am middleware.AuthMiddleware) {
+	serviceTierRoutes := router.Group("/service-tiers", am.RequireToken())
+	{
+		serviceTierRoutes.GET("/",sc.ListServiceTier)
+		serviceTierRoutes.POST("/",am.RequireRole(entity.Role_admin),sc.CreateServiceTier)
+		serviceTierRoutes.PUT("/:code",am.RequireRole(entity.Role_admin),sc.UpdateServiceTier)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"submission-project-enigma-laundry/controller"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+
+func Supply(router *gin.Engine, sc controller.SupplyController, am middleware.AuthMiddleware) {
+	supplyRoutes := router.Group("/supplies", am.RequireToken())
+	{
+		supplyRoutes.GET("/",sc.ListSupply)
+		supplyRoutes.GET("/low-stock",sc.GetLowStockReport)
+		supplyRoutes.GET("/:id",sc.GetSupply)
+		supplyRoutes.POST("/",am.RequireRole(entity.Role_admin),sc.CreateSupply)
+		supplyRoutes.PUT("/:id",am.RequireRole(entity.Role_admin),sc.UpdateSupply)
+		supplyRoutes.POST("/:id/adjustments",am.RequireRole(entity.Role_admin, entity.Role_cashier),sc.AdjustStock)
+	}
+
+	// Bill of materials of a product
+	productSupplyRoutes := router.Group("/products/:id/supplies", am.RequireToken())
+	{
+		productSupplyRoutes.GET("",sc.GetProductSupplies)
+		productSupplyRoutes.PUT("",am.RequireRole(entity.Role_admin),sc.SetProductSupplies)
+	}
+}
\ No newline at end of file
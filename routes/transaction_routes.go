@@ -1,17 +1,26 @@
-package routes
-
-import (
-	"submission-project-enigma-laundry/controller"
-
-	"github.com/gin-gonic/gin"
-)
-
-
-func Transaction(router *gin.Engine, tc controller.TransactionController) {
-	transactionRoutes := router.Group("/transactions")
-	{
-		transactionRoutes.POST("/",tc.CreateTransaction)
-		transactionRoutes.GET("/:id_bill",tc.GetTransaction)
-		transactionRoutes.GET("/",tc.ListTransaction)
-	}
+package routes
+
+import (
+	"submission-project-enigma-laundry/controller"
+	"submission-project-enigma-laundry/entity"
+	"submission-project-enigma-laundry/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+
+func Transaction(router *gin.Engine, tc controller.TransactionController, am middleware.AuthMiddleware) {
+	cashier := am.RequireRole(entity.Role_admin, entity.Role_cashier)
+
+	transactionRoutes := router.Group("/transactions", am.RequireToken())
+	{
+		transactionRoutes.POST("/",cashier,tc.CreateTransaction)
+		transactionRoutes.GET("/:id_bill",tc.GetTransaction)
+		transactionRoutes.GET("/:id_bill/receipt",tc.GetReceipt)
+		transactionRoutes.GET("/",tc.ListTransaction)
+		transactionRoutes.PUT("/:id_bill",cashier,tc.UpdateTransaction)
+		transactionRoutes.POST("/:id_bill/cancel",cashier,tc.CancelTransaction)
+		transactionRoutes.PATCH("/:id_bill/status",am.RequireRole(entity.Role_admin, entity.Role_cashier, entity.Role_washer),tc.UpdateTransactionStatus)
+		transactionRoutes.POST("/:id_bill/payments",cashier,tc.CreatePayment)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"submission-project-enigma-laundry/config"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Kind of token, an access token can not be used to refresh and the other way around
+const (
+	Token_access  = "access"
+	Token_refresh = "refresh"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+type TokenClaims struct {
+	Employee_id int      `json:"employeeId"`
+	Roles       []string `json:"roles,omitempty"`
+	Token_type  string   `json:"tokenType"`
+	jwt.RegisteredClaims
+}
+
+type TokenPair struct {
+	Access_token  string `json:"accessToken"`
+	Refresh_token string `json:"refreshToken"`
+	Expires_in    int    `json:"expiresIn"`
+}
+
+// Roles only go into the access token, refreshing reads them again from the database
+func GenerateTokenPair(jwtConfig config.JwtConfig, employeeId int, roles []string) (TokenPair, error) {
+	pair := TokenPair{Expires_in: int(jwtConfig.Access_ttl.Seconds())}
+
+	var err error
+	pair.Access_token, err = generateToken(jwtConfig.Secret, employeeId, roles, Token_access, jwtConfig.Access_ttl)
+	if err != nil {
+		return pair, err
+	}
+	pair.Refresh_token, err = generateToken(jwtConfig.Secret, employeeId, nil, Token_refresh, jwtConfig.Refresh_ttl)
+	if err != nil {
+		return pair, err
+	}
+	return pair, nil
+}
+
+func generateToken(secret []byte, employeeId int, roles []string, tokenType string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := TokenClaims{
+		Employee_id: employeeId,
+		Roles:       roles,
+		Token_type:  tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.Itoa(employeeId),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+}
+
+// Validate signature, expiry and type of the token and return its claims
+func ParseToken(secret []byte, tokenString string, tokenType string) (*TokenClaims, error) {
+	claims := &TokenClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, fmt.Errorf("%w, %s", ErrInvalidToken, err)
+	}
+
+	if claims.Token_type != tokenType {
+		return nil, fmt.Errorf("%w, expected %s token", ErrInvalidToken, tokenType)
+	}
+	return claims, nil
+}
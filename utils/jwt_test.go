@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"errors"
+	"submission-project-enigma-laundry/config"
+	"testing"
+	"time"
+)
+
+var testJwtConfig = config.JwtConfig{
+	Secret:      []byte("test-secret"),
+	Access_ttl:  time.Minute,
+	Refresh_ttl: time.Hour,
+}
+
+func TestGenerateTokenPair(t *testing.T) {
+	pair, err := GenerateTokenPair(testJwtConfig, 7, []string{"admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pair.Expires_in != 60 {
+		t.Fatalf("expected expiresIn 60, got %d", pair.Expires_in)
+	}
+
+	access, err := ParseToken(testJwtConfig.Secret, pair.Access_token, Token_access)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if access.Employee_id != 7 || len(access.Roles) != 1 || access.Roles[0] != "admin" || access.Subject != "7" {
+		t.Fatalf("unexpected access claims %+v", access)
+	}
+
+	refresh, err := ParseToken(testJwtConfig.Secret, pair.Refresh_token, Token_refresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refresh.Employee_id != 7 || len(refresh.Roles) != 0 {
+		t.Fatalf("unexpected refresh claims %+v", refresh)
+	}
+}
+
+func TestParseTokenRejects(t *testing.T) {
+	access, err := generateToken(testJwtConfig.Secret, 7, nil, Token_access, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refresh, err := generateToken(testJwtConfig.Secret, 7, nil, Token_refresh, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := generateToken(testJwtConfig.Secret, 7, nil, Token_access, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSecret, err := generateToken([]byte("other-secret"), 7, nil, Token_access, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		token     string
+		tokenType string
+	}{
+		{"expired", expired, Token_access},
+		{"refresh used as access", refresh, Token_access},
+		{"access used as refresh", access, Token_refresh},
+		{"bad signature", otherSecret, Token_access},
+		{"tampered", access[:len(access)-2] + "xx", Token_access},
+		{"malformed", "not-a-token", Token_access},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claims, err := ParseToken(testJwtConfig.Secret, test.token, test.tokenType)
+			if !errors.Is(err, ErrInvalidToken) || claims != nil {
+				t.Fatalf("expected invalid token, got %v %+v", err, claims)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Country code of numbers written without one
+const DefaultCountryCode = "62"
+
+// Returned when a phone number can not be written in E.164
+var ErrInvalidPhoneNumber = errors.New("invalid phone number")
+
+// Normalize a phone number to E.164, ex : 0812-3456-7890 and 62 812 3456 7890 are +6281234567890.
+// Numbers without a country code are Indonesian, written with a leading 0 or starting at the 8 of a mobile number
+func NormalizePhoneNumber(phoneNumber string) (string, error) {
+	number := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(phoneNumber))
+
+	switch {
+	case strings.HasPrefix(number, "+"):
+		number = number[1:]
+	case strings.HasPrefix(number, "00"):
+		number = number[2:]
+	case strings.HasPrefix(number, DefaultCountryCode):
+	case strings.HasPrefix(number, "0"):
+		number = DefaultCountryCode + number[1:]
+	case strings.HasPrefix(number, "8"):
+		number = DefaultCountryCode + number
+	default:
+		return "", fmt.Errorf("%w, it must start with +, 00, 62, 0 or 8", ErrInvalidPhoneNumber)
+	}
+
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("%w, it can only have digits after the country code", ErrInvalidPhoneNumber)
+		}
+	}
+	// E.164 allows at most 15 digits with the country code
+	if len(number) < 8 || len(number) > 15 || number[0] == '0' {
+		return "", fmt.Errorf("%w, it must have 8 to 15 digits with the country code", ErrInvalidPhoneNumber)
+	}
+	return "+" + number, nil
+}
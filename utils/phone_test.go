@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	tests := []struct {
+		name        string
+		phoneNumber string
+		expected    string
+		err         error
+	}{
+		{"leading 0", "081234567890", "+6281234567890", nil},
+		{"leading 0 with dashes", "0812-3456-7890", "+6281234567890", nil},
+		{"country code without plus", "6281234567890", "+6281234567890", nil},
+		{"country code with spaces", "62 812 3456 7890", "+6281234567890", nil},
+		{"plus with spaces and dashes", "+62 812-3456-7890", "+6281234567890", nil},
+		{"international prefix", "006281234567890", "+6281234567890", nil},
+		{"mobile number without 0", "81234567890", "+6281234567890", nil},
+		{"other country", "+1 (415) 555-0100", "+14155550100", nil},
+		{"local number without area code", "555-1234", "", ErrInvalidPhoneNumber},
+		{"letters", "0812-abcd-7890", "", ErrInvalidPhoneNumber},
+		{"too short", "0812", "", ErrInvalidPhoneNumber},
+		{"too long", "+6281234567890123", "", ErrInvalidPhoneNumber},
+		{"country code 0", "+0812345678", "", ErrInvalidPhoneNumber},
+		{"empty", "", "", ErrInvalidPhoneNumber},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			normalized, err := NormalizePhoneNumber(test.phoneNumber)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+			if normalized != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, normalized)
+			}
+		})
+	}
+}
@@ -0,0 +1,357 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"submission-project-enigma-laundry/config"
+	"submission-project-enigma-laundry/entity"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Receipt paper formats
+const (
+	Receipt_58mm = "58mm"
+	Receipt_80mm = "80mm"
+	Receipt_a4   = "a4"
+)
+
+var ErrInvalidReceiptFormat = errors.New("format must be one of 58mm, 80mm, a4")
+
+func IsValidReceiptFormat(format string) bool {
+	switch format {
+	case Receipt_58mm, Receipt_80mm, Receipt_a4:
+		return true
+	}
+	return false
+}
+
+// Write the bill as PDF, a thermal roll receipt for 58mm and 80mm or an A4 invoice
+func RenderReceipt(w io.Writer, transaction *entity.Transaction, shop config.ShopInfo, format string) error {
+	var pdf *fpdf.Fpdf
+	switch format {
+	case Receipt_58mm:
+		pdf = thermalReceipt(transaction, shop, 58)
+	case Receipt_80mm:
+		pdf = thermalReceipt(transaction, shop, 80)
+	case Receipt_a4:
+		pdf = renderInvoice(transaction, shop)
+	default:
+		return ErrInvalidReceiptFormat
+	}
+
+	return pdf.Output(w)
+}
+
+// Roll paper has no fixed length, render once on a long page to measure the content
+// then again on a page cut right after it
+func thermalReceipt(transaction *entity.Transaction, shop config.ShopInfo, width float64) *fpdf.Fpdf {
+	draft := renderThermalReceipt(transaction, shop, width, 5000)
+	return renderThermalReceipt(transaction, shop, width, draft.GetY()+thermalMargin)
+}
+
+const thermalMargin = 3.0
+
+func renderThermalReceipt(transaction *entity.Transaction, shop config.ShopInfo, width float64, height float64) *fpdf.Fpdf {
+	const margin, lineHeight = thermalMargin, 4.0
+	fontSize := 8.0
+	if width < 80 {
+		fontSize = 7
+	}
+
+	pdf := fpdf.NewCustom(&fpdf.InitType{UnitStr: "mm", Size: fpdf.SizeType{Wd: width, Ht: height}})
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(false, margin)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	contentWidth := width - 2*margin
+
+	if logo, ok := logoOf(pdf, shop); ok {
+		logoWidth := contentWidth / 2
+		pdf.ImageOptions(shop.Logo_path, (width-logoWidth)/2, pdf.GetY(), logoWidth, 0, true, logo, 0, "")
+	}
+
+	pdf.SetFont("Helvetica", "B", fontSize+2)
+	pdf.CellFormat(contentWidth, lineHeight+1, tr(shop.Name), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", fontSize)
+	for _, line := range []string{shop.Address, shop.Phone} {
+		if line != "" {
+			pdf.MultiCell(contentWidth, lineHeight, tr(line), "", "C", false)
+		}
+	}
+	thermalRule(pdf, margin, width)
+
+	billDate, entryDate, finishDate := receiptDates(transaction)
+	for _, row := range [][2]string{
+		{"Bill", transaction.Bill_number},
+		{"Date", billDate},
+		{"Entry", entryDate},
+		{"Finish", finishDate},
+		{"Cashier", transaction.Employee.Name},
+		{"Customer", transaction.Customer.Name},
+		{"Phone", transaction.Customer.Phone_number},
+		{"Status", transaction.Status},
+	} {
+		pdf.CellFormat(contentWidth*0.35, lineHeight, row[0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(contentWidth*0.65, lineHeight, tr(row[1]), "", 1, "R", false, 0, "")
+	}
+	thermalRule(pdf, margin, width)
+
+	for _, billDetail := range transaction.Bill_detail {
+		pdf.MultiCell(contentWidth, lineHeight, tr(detailName(billDetail)), "", "L", false)
+		qty := fmt.Sprintf("%s %s x %s", formatQty(billDetail.Qty), billDetail.Product.Unit, FormatRupiah(billDetail.Product_price))
+		pdf.CellFormat(contentWidth*0.6, lineHeight, tr(qty), "", 0, "L", false, 0, "")
+		pdf.CellFormat(contentWidth*0.4, lineHeight, FormatRupiah(billDetail.Line_total), "", 1, "R", false, 0, "")
+	}
+	thermalRule(pdf, margin, width)
+
+	for _, line := range billBreakdown(transaction) {
+		thermalTotal(pdf, contentWidth, lineHeight, line.label, line.amount)
+	}
+	pdf.SetFont("Helvetica", "B", fontSize)
+	thermalTotal(pdf, contentWidth, lineHeight, "Total", transaction.Total_bill)
+	pdf.SetFont("Helvetica", "", fontSize)
+	for _, payment := range transaction.Payments {
+		thermalTotal(pdf, contentWidth, lineHeight, "Paid ("+payment.Method+")", payment.Amount)
+	}
+	thermalTotal(pdf, contentWidth, lineHeight, "Outstanding", transaction.Outstanding)
+	pdf.CellFormat(contentWidth, lineHeight, strings.ToUpper(transaction.Payment_status), "", 1, "R", false, 0, "")
+	thermalRule(pdf, margin, width)
+
+	pdf.CellFormat(contentWidth, lineHeight, "Thank you", "", 1, "C", false, 0, "")
+	return pdf
+}
+
+type receiptLine struct {
+	label  string
+	amount int
+}
+
+// Lines between the bill details and the total, tax of inclusive products is shown but not added
+func billBreakdown(transaction *entity.Transaction) []receiptLine {
+	addedTax := transaction.Total_bill - transaction.AmountBeforeTax()
+	includedTax := transaction.Tax - addedTax
+	taxRate := strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", float64(transaction.Tax_rate)/100), "0"), ".")
+
+	lines := []receiptLine{}
+	if transaction.Surcharge == 0 && transaction.Discount == 0 && transaction.Member_discount == 0 && transaction.Points_discount == 0 && transaction.Tax == 0 {
+		return lines
+	}
+	lines = append(lines, receiptLine{"Subtotal", transaction.Subtotal})
+	if transaction.Surcharge > 0 {
+		lines = append(lines, receiptLine{surchargeLabel(transaction), transaction.Surcharge})
+	}
+	if transaction.Discount > 0 {
+		lines = append(lines, receiptLine{"Discount " + transaction.Promo_code, -transaction.Discount})
+	}
+	if transaction.Member_discount > 0 {
+		lines = append(lines, receiptLine{fmt.Sprintf("Member %s %d%%", transaction.Member_tier, transaction.Member_discount_percent), -transaction.Member_discount})
+	}
+	if transaction.Points_discount > 0 {
+		lines = append(lines, receiptLine{fmt.Sprintf("Points %d", transaction.Points_redeemed), -transaction.Points_discount})
+	}
+	if addedTax > 0 {
+		lines = append(lines, receiptLine{"PPN " + taxRate + "%", addedTax})
+	}
+	if includedTax > 0 {
+		lines = append(lines, receiptLine{"Incl. PPN " + taxRate + "%", includedTax})
+	}
+	return lines
+}
+
+// Ex : Surcharge express 50%
+func surchargeLabel(transaction *entity.Transaction) string {
+	label := "Surcharge " + strings.ReplaceAll(transaction.Service_tier, "_", " ")
+	if transaction.Surcharge_type == entity.Surcharge_percent {
+		label += fmt.Sprintf(" %d%%", transaction.Surcharge_value)
+	}
+	return label
+}
+
+func thermalRule(pdf *fpdf.Fpdf, margin float64, width float64) {
+	y := pdf.GetY() + 1
+	pdf.SetDashPattern([]float64{0.8, 0.8}, 0)
+	pdf.Line(margin, y, width-margin, y)
+	pdf.SetDashPattern([]float64{}, 0)
+	pdf.SetY(y + 1)
+}
+
+func thermalTotal(pdf *fpdf.Fpdf, contentWidth float64, lineHeight float64, label string, amount int) {
+	pdf.CellFormat(contentWidth*0.5, lineHeight, label, "", 0, "L", false, 0, "")
+	pdf.CellFormat(contentWidth*0.5, lineHeight, FormatRupiah(amount), "", 1, "R", false, 0, "")
+}
+
+func renderInvoice(transaction *entity.Transaction, shop config.ShopInfo) *fpdf.Fpdf {
+	const margin, lineHeight = 15.0, 6.0
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pageWidth, _ := pdf.GetPageSize()
+	contentWidth := pageWidth - 2*margin
+
+	// Logo on the left, shop header on the right
+	top := pdf.GetY()
+	if logo, ok := logoOf(pdf, shop); ok {
+		pdf.ImageOptions(shop.Logo_path, margin, top, 40, 0, false, logo, 0, "")
+	}
+	pdf.SetXY(margin+50, top)
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(contentWidth-50, 8, tr(shop.Name), "", 2, "R", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	for _, line := range []string{shop.Address, shop.Phone} {
+		if line != "" {
+			pdf.CellFormat(contentWidth-50, 5, tr(line), "", 2, "R", false, 0, "")
+		}
+	}
+	pdf.SetY(top + 35)
+
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(contentWidth, 8, "INVOICE", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+
+	billDate, entryDate, finishDate := receiptDates(transaction)
+	left := [][2]string{
+		{"Bill", transaction.Bill_number},
+		{"Bill Date", billDate},
+		{"Entry Date", entryDate},
+		{"Finish Date", finishDate},
+	}
+	right := [][2]string{
+		{"Customer", transaction.Customer.Name},
+		{"Phone", transaction.Customer.Phone_number},
+		{"Address", transaction.Customer.Address},
+		{"Cashier", transaction.Employee.Name},
+	}
+	for i := range left {
+		pdf.CellFormat(25, lineHeight, left[i][0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(contentWidth/2-25, lineHeight, tr(left[i][1]), "", 0, "L", false, 0, "")
+		pdf.CellFormat(25, lineHeight, right[i][0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(contentWidth/2-25, lineHeight, tr(right[i][1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(lineHeight)
+
+	columns := []struct {
+		title string
+		width float64
+		align string
+	}{
+		{"No", 10, "C"},
+		{"Product", contentWidth - 120, "L"},
+		{"Qty", 20, "R"},
+		{"Unit", 25, "L"},
+		{"Price", 30, "R"},
+		{"Subtotal", 35, "R"},
+	}
+
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(230, 230, 230)
+	for _, column := range columns {
+		pdf.CellFormat(column.width, lineHeight+1, column.title, "1", 0, column.align, true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 10)
+	for i, billDetail := range transaction.Bill_detail {
+		values := []string{
+			strconv.Itoa(i + 1),
+			tr(detailName(billDetail)),
+			formatQty(billDetail.Qty),
+			tr(billDetail.Product.Unit),
+			FormatRupiah(billDetail.Product_price),
+			FormatRupiah(billDetail.Line_total),
+		}
+		for j, column := range columns {
+			pdf.CellFormat(column.width, lineHeight, values[j], "1", 0, column.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	pdf.Ln(2)
+
+	invoiceTotal := func(label string, amount int) {
+		pdf.CellFormat(contentWidth-35, lineHeight, label, "", 0, "R", false, 0, "")
+		pdf.CellFormat(35, lineHeight, FormatRupiah(amount), "", 1, "R", false, 0, "")
+	}
+
+	for _, line := range billBreakdown(transaction) {
+		invoiceTotal(line.label, line.amount)
+	}
+	pdf.SetFont("Helvetica", "B", 10)
+	invoiceTotal("Total", transaction.Total_bill)
+	pdf.SetFont("Helvetica", "", 10)
+	for _, payment := range transaction.Payments {
+		invoiceTotal(fmt.Sprintf("Paid %s (%s)", payment.Paid_at.Format("02-01-2006"), payment.Method), payment.Amount)
+	}
+	pdf.SetFont("Helvetica", "B", 10)
+	invoiceTotal("Outstanding", transaction.Outstanding)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(contentWidth, lineHeight, "Payment status : "+transaction.Payment_status+"   Order status : "+transaction.Status, "", 1, "R", false, 0, "")
+
+	if transaction.Cancel_reason != "" {
+		pdf.Ln(lineHeight)
+		pdf.MultiCell(contentWidth, lineHeight, tr("Cancelled : "+transaction.Cancel_reason), "", "L", false)
+	}
+
+	return pdf
+}
+
+// Register the shop logo, a missing logo file only leaves the receipt without logo
+func logoOf(pdf *fpdf.Fpdf, shop config.ShopInfo) (fpdf.ImageOptions, bool) {
+	options := fpdf.ImageOptions{}
+	if shop.Logo_path == "" {
+		return options, false
+	}
+	if _, err := os.Stat(shop.Logo_path); err != nil {
+		return options, false
+	}
+
+	info := pdf.RegisterImageOptions(shop.Logo_path, options)
+	if info == nil || !pdf.Ok() {
+		pdf.ClearError()
+		return options, false
+	}
+	return options, true
+}
+
+func receiptDates(transaction *entity.Transaction) (string, string, string) {
+	dateLayout, dateTimeLayout := config.DateFormat()
+	return transaction.Bill_date.Format(dateLayout), transaction.Entry_date.Format(dateTimeLayout), transaction.Finish_date.Format(dateTimeLayout)
+}
+
+// Weight keeps its decimals, ex : 2.5 kg and 3 pcs
+// Weight covered by a package is shown next to the product name
+func detailName(billDetail entity.Transaction_detail) string {
+	if billDetail.Quota_used <= 0 {
+		return billDetail.Product.Product_name
+	}
+	return fmt.Sprintf("%s (package %s kg)", billDetail.Product.Product_name, formatQty(billDetail.Quota_used))
+}
+
+func formatQty(qty float64) string {
+	return strconv.FormatFloat(qty, 'f', -1, 64)
+}
+
+// Ex : 1500000 is written as Rp 1.500.000
+func FormatRupiah(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := strconv.Itoa(amount)
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	return sign + "Rp " + grouped.String()
+}
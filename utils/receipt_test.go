@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"submission-project-enigma-laundry/config"
+	"submission-project-enigma-laundry/entity"
+	"testing"
+	"time"
+)
+
+func TestFormatRupiah(t *testing.T) {
+	tests := []struct {
+		amount   int
+		expected string
+	}{
+		{0, "Rp 0"},
+		{500, "Rp 500"},
+		{1000, "Rp 1.000"},
+		{35000, "Rp 35.000"},
+		{999999, "Rp 999.999"},
+		{1500000, "Rp 1.500.000"},
+		{-2500, "-Rp 2.500"},
+	}
+
+	for _, test := range tests {
+		if got := FormatRupiah(test.amount); got != test.expected {
+			t.Fatalf("%d expected %q, got %q", test.amount, test.expected, got)
+		}
+	}
+}
+
+func TestRenderReceipt(t *testing.T) {
+	billDate := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	transaction := entity.Transaction{
+		Transaction_id: "1",
+		Bill_number:    "EL-JKT-20241001-0001",
+		Bill_date:      billDate,
+		Entry_date:     billDate.Add(9 * time.Hour),
+		Finish_date:    billDate.Add(57 * time.Hour),
+		Status:         entity.Status_received,
+		Employee:       entity.Employee{Name: "Budi"},
+		Customer:       entity.Customer{Name: "Siti Rahayu", Phone_number: "+6281234567890"},
+		Bill_detail: []entity.Transaction_detail{
+			{Product: entity.Product{Product_name: "Cuci Kering", Unit: "kg"}, Product_price: 7000, Qty: 2.5, Line_total: 21000, Quota_used: 1},
+			{Product: entity.Product{Product_name: "Bed Cover", Unit: "pcs"}, Product_price: 25000, Qty: 1, Line_total: 25000},
+		},
+		Subtotal:       46000,
+		Tax_rate:       1100,
+		Tax:            5060,
+		Total_bill:     51060,
+		Paid_amount:    20000,
+		Outstanding:    31060,
+		Payment_status: entity.Payment_status_partial,
+		Payments:       []entity.Payment{{Method: entity.Payment_method_cash, Amount: 20000}},
+	}
+	shop := config.ShopInfo{Name: "Enigma Laundry", Address: "Jl. Sudirman No. 1, Jakarta", Phone: "021-1234567", Logo_path: "../asset/Enigma-Laundry.png"}
+
+	for _, format := range []string{Receipt_58mm, Receipt_80mm, Receipt_a4} {
+		t.Run(format, func(t *testing.T) {
+			var pdf bytes.Buffer
+			err := RenderReceipt(&pdf, &transaction, shop, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pdf.Len() == 0 || !bytes.HasPrefix(pdf.Bytes(), []byte("%PDF-")) {
+				t.Fatalf("expected a pdf, got %d bytes", pdf.Len())
+			}
+		})
+	}
+
+	err := RenderReceipt(&bytes.Buffer{}, &transaction, shop, "a5")
+	if !errors.Is(err, ErrInvalidReceiptFormat) {
+		t.Fatalf("expected %v, got %v", ErrInvalidReceiptFormat, err)
+	}
+}